@@ -2,9 +2,11 @@ package agentboot
 
 import (
 	"context"
+	"sync"
 
 	"github.com/SaiNageswarS/agent-boot/llm"
 	"github.com/SaiNageswarS/agent-boot/memory"
+	"github.com/SaiNageswarS/agent-boot/prompts"
 	"github.com/SaiNageswarS/agent-boot/schema"
 	"github.com/ollama/ollama/api"
 )
@@ -18,14 +20,81 @@ type AgentConfig struct {
 	Tools        []MCPTool
 	MaxTokens    int
 	MaxTurns     int
+	Temperature  float64
+
+	// MaxParallelTools limits how many tool calls of a single turn run concurrently.
+	// Zero or negative runs all calls of a turn at once.
+	MaxParallelTools int
+
+	// ModelPrices maps model names to their price, used to report the cost of each request.
+	ModelPrices map[string]ModelPrice
+
+	// AnswerSchema is the JSON schema the final answer must conform to. When set, the answer is
+	// validated and returned as JSON in StreamComplete.Metadata[MetadataAnswerJSON].
+	AnswerSchema map[string]any
+
+	// AnswerRepairAttempts is how many times an answer not conforming to AnswerSchema is sent
+	// back to the model to be fixed.
+	AnswerRepairAttempts int
 
 	// Conversation management
 	ConversationManager *memory.ConversationManager
+
+	// LongTermMemory remembers facts about users across sessions. The MemoryTopK facts most
+	// relevant to a question are given to the models, and new facts are learned from each
+	// completed turn by the mini model in the background, after the answer is sent. Only
+	// requests with a user ID use it.
+	LongTermMemory *memory.LongTermMemory
+	MemoryTopK     int
+
+	// TrimPolicy fits the conversation into the model context before each LLM call, such as a
+	// memory.TokenBudget. The saved conversation is not trimmed. Nil sends all messages.
+	TrimPolicy memory.TrimPolicy
+
+	// Prompts renders the prompts of the agent, such as a registry overriding the tool selection
+	// template. Nil uses the embedded templates.
+	Prompts *prompts.Registry
+}
+
+// summaryModel returns the model summarizing conversations, the mini model when there is one,
+// and the role its usage is reported under.
+func (c AgentConfig) summaryModel() (llm.LLMClient, string) {
+	if c.MiniModel == nil {
+		return c.BigModel, UsageRoleBigModel
+	}
+	return c.MiniModel, UsageRoleMiniModel
 }
 
 // Agent represents the main agent system
 type Agent struct {
 	config AgentConfig
+	usage  *usageTracker // token usage of the request being executed
+
+	metadata map[string]string // metadata of the request being executed, given to prompt templates
+
+	background *sync.WaitGroup // work left running by completed requests, such as learning memories
+}
+
+// Wait blocks until the background work of completed requests, such as learning long-term
+// memories, is done. Call it before exiting so that work is not lost.
+func (a *Agent) Wait() {
+	if a.background != nil {
+		a.background.Wait()
+	}
+}
+
+// goBackground runs fn after the request, tracked by Wait.
+func (a *Agent) goBackground(fn func()) {
+	if a.background == nil {
+		go fn()
+		return
+	}
+
+	a.background.Add(1)
+	go func() {
+		defer a.background.Done()
+		fn()
+	}()
 }
 
 // MCPTool wraps an api.Tool and provides a handler for execution
@@ -35,5 +104,13 @@ type MCPTool struct {
 	// When enabled, each ToolResult's Sentences will be summarized with respect to the user's query.
 	// Irrelevant content will be filtered out, making this ideal for RAG search and web search tools.
 	SummarizeContext bool `json:"summarize_context"`
-	Handler          func(ctx context.Context, params api.ToolCallFunctionArguments) <-chan *schema.ToolResultChunk
+	// InputSchema is the complete JSON schema of the parameters, including bounds, defaults and
+	// nested object properties that api.ToolProperty cannot carry. Arguments are validated against
+	// it when set, otherwise against Function.Parameters.
+	InputSchema map[string]any `json:"input_schema,omitempty"`
+	// Handler runs the tool and returns the channel its results are sent to, closed when done. A
+	// panic in Handler itself is recovered, but not one in a goroutine it starts to fill the
+	// channel; start those with StreamToolResults, which recovers them. Sends should stop when ctx
+	// is done, since the results are no longer read then.
+	Handler func(ctx context.Context, params api.ToolCallFunctionArguments) <-chan *schema.ToolResultChunk
 }
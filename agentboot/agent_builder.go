@@ -1,20 +1,29 @@
 package agentboot
 
 import (
+	"sync"
+
 	"github.com/SaiNageswarS/agent-boot/llm"
 	"github.com/SaiNageswarS/agent-boot/memory"
+	"github.com/SaiNageswarS/agent-boot/prompts"
 	"github.com/SaiNageswarS/go-api-boot/odm"
 )
 
 type AgentBuilder struct {
 	config AgentConfig
+
+	summaryThreshold int
+	summaryKeepTurns int
 }
 
 func NewAgentBuilder() *AgentBuilder {
 	return &AgentBuilder{
 		config: AgentConfig{
-			MaxTurns:  5,
-			MaxTokens: 2000,
+			MaxTurns:             5,
+			MaxTokens:            2000,
+			Temperature:          0.7,
+			MaxParallelTools:     4,
+			AnswerRepairAttempts: 2,
 		},
 	}
 }
@@ -54,11 +63,79 @@ func (b *AgentBuilder) WithMaxTurns(maxTurns int) *AgentBuilder {
 	return b
 }
 
+func (b *AgentBuilder) WithTemperature(temperature float64) *AgentBuilder {
+	b.config.Temperature = temperature
+	return b
+}
+
+// WithModelPrice sets the price of a model, so the cost of each request is reported.
+func (b *AgentBuilder) WithModelPrice(model string, price ModelPrice) *AgentBuilder {
+	if b.config.ModelPrices == nil {
+		b.config.ModelPrices = map[string]ModelPrice{}
+	}
+	b.config.ModelPrices[model] = price
+	return b
+}
+
+func (b *AgentBuilder) WithMaxParallelTools(limit int) *AgentBuilder {
+	b.config.MaxParallelTools = limit
+	return b
+}
+
+// WithAnswerSchema requires the final answer to be JSON conforming to schema, e.g. one built
+// with SchemaOf. Decode the answer with ParseAnswer.
+func (b *AgentBuilder) WithAnswerSchema(schema map[string]any) *AgentBuilder {
+	b.config.AnswerSchema = schema
+	return b
+}
+
+func (b *AgentBuilder) WithAnswerRepairAttempts(attempts int) *AgentBuilder {
+	b.config.AnswerRepairAttempts = attempts
+	return b
+}
+
 func (b *AgentBuilder) WithConversationManager(collection odm.OdmCollectionInterface[memory.Conversation], maxMsgs int) *AgentBuilder {
 	b.config.ConversationManager = memory.NewConversationManager(collection, maxMsgs)
 	return b
 }
 
+// WithConversationStore keeps sessions in store, such as a memory.InMemoryStore or memory.FileStore,
+// retaining the last maxMsgs user turns of each.
+func (b *AgentBuilder) WithConversationStore(store memory.Store, maxMsgs int) *AgentBuilder {
+	b.config.ConversationManager = memory.NewConversationManagerWithStore(store, maxMsgs)
+	return b
+}
+
+// WithConversationSummary folds the oldest turns of sessions with more than threshold user turns
+// into a running summary written by the mini model, keeping the latest keepTurns turns verbatim.
+// It applies to the conversation manager or store configured on the builder.
+func (b *AgentBuilder) WithConversationSummary(threshold, keepTurns int) *AgentBuilder {
+	b.summaryThreshold = threshold
+	b.summaryKeepTurns = keepTurns
+	return b
+}
+
+// WithLongTermMemory remembers facts about users across sessions, giving the topK facts relevant to
+// each question to the models. Facts are learned by the mini model.
+func (b *AgentBuilder) WithLongTermMemory(mem *memory.LongTermMemory, topK int) *AgentBuilder {
+	b.config.LongTermMemory = mem
+	b.config.MemoryTopK = topK
+	return b
+}
+
+// WithTrimPolicy fits the conversation into the model context before each LLM call.
+func (b *AgentBuilder) WithTrimPolicy(policy memory.TrimPolicy) *AgentBuilder {
+	b.config.TrimPolicy = policy
+	return b
+}
+
+// WithPrompts renders the prompts of the agent, its conversation summaries and long-term memory
+// with registry, whose templates may override the embedded ones.
+func (b *AgentBuilder) WithPrompts(registry *prompts.Registry) *AgentBuilder {
+	b.config.Prompts = registry
+	return b
+}
+
 // Deprecated: Use WithConversationManager instead
 func (b *AgentBuilder) WithMaxSessionMessages(max int) *AgentBuilder {
 	if b.config.ConversationManager != nil {
@@ -72,5 +149,19 @@ func (b *AgentBuilder) Build() *Agent {
 		b.config.ToolSelector = llm.NewOllamaClient("gpt-oss:20b") // Default tool selector
 	}
 
-	return &Agent{config: b.config}
+	if b.summaryThreshold > 0 && b.config.ConversationManager != nil {
+		summaryModel, _ := b.config.summaryModel()
+		b.config.ConversationManager.SetSummarizer(summaryModel, b.summaryThreshold, b.summaryKeepTurns)
+	}
+
+	if b.config.Prompts != nil {
+		if b.config.ConversationManager != nil {
+			b.config.ConversationManager.SetPrompts(b.config.Prompts)
+		}
+		if b.config.LongTermMemory != nil {
+			b.config.LongTermMemory.SetPrompts(b.config.Prompts)
+		}
+	}
+
+	return &Agent{config: b.config, background: &sync.WaitGroup{}}
 }
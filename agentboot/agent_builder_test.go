@@ -1,10 +1,13 @@
 package agentboot
 
 import (
+	"context"
 	"testing"
 
+	"github.com/SaiNageswarS/agent-boot/memory"
 	"github.com/ollama/ollama/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewAgentBuilder(t *testing.T) {
@@ -83,6 +86,36 @@ func TestAgentBuilderWithMaxTurns(t *testing.T) {
 	assert.Equal(t, 10, builder.config.MaxTurns)
 }
 
+func TestAgentBuilderWithTemperature(t *testing.T) {
+	builder := NewAgentBuilder()
+	assert.Equal(t, 0.7, builder.config.Temperature)
+
+	result := builder.WithTemperature(0.2)
+
+	assert.Equal(t, builder, result) // Should return self for chaining
+	assert.Equal(t, 0.2, builder.config.Temperature)
+}
+
+func TestAgentBuilderWithMaxParallelTools(t *testing.T) {
+	builder := NewAgentBuilder()
+	assert.Equal(t, 4, builder.config.MaxParallelTools)
+
+	result := builder.WithMaxParallelTools(2)
+
+	assert.Equal(t, builder, result) // Should return self for chaining
+	assert.Equal(t, 2, builder.config.MaxParallelTools)
+}
+
+func TestAgentBuilderWithModelPrice(t *testing.T) {
+	builder := NewAgentBuilder()
+	price := ModelPrice{InputPerMillion: 3, OutputPerMillion: 15}
+
+	result := builder.WithModelPrice("claude-sonnet-4", price)
+
+	assert.Equal(t, builder, result) // Should return self for chaining
+	assert.Equal(t, map[string]ModelPrice{"claude-sonnet-4": price}, builder.config.ModelPrices)
+}
+
 func TestAgentBuilderBuild(t *testing.T) {
 	mockMiniModel := &mockLLMClient{model: "mini"}
 	mockBigModel := &mockLLMClient{model: "big"}
@@ -175,3 +208,26 @@ func TestAgentBuilderOverrideValues(t *testing.T) {
 	assert.Equal(t, 2000, builder.config.MaxTokens)
 	assert.Equal(t, 8, builder.config.MaxTurns)
 }
+
+func TestAgentBuilderWithConversationSummary(t *testing.T) {
+	miniModel := &testLLMClient{model: "mini", response: "The user asked about questions 1 and 2."}
+	store := memory.NewInMemoryStore(0)
+	agent := NewAgentBuilder().
+		WithBigModel(&testLLMClient{model: "big"}).
+		WithToolSelector(&testLLMClient{model: "selector"}).
+		WithMiniModel(miniModel).
+		WithConversationSummary(2, 1).
+		WithConversationStore(store, 10).
+		Build()
+
+	conversation := &memory.Conversation{ID: "s1"}
+	for _, question := range []string{"Question 1", "Question 2", "Question 3"} {
+		conversation.AddUserMessage(question)
+		conversation.AddAssistantMessage("Answer")
+	}
+	require.NoError(t, agent.config.ConversationManager.SaveSession(context.Background(), conversation))
+
+	assert.Equal(t, 1, miniModel.callCount, "The mini model summarizes, whatever the order of the builder calls")
+	assert.Equal(t, "The user asked about questions 1 and 2.", conversation.Summary)
+	assert.Len(t, conversation.Messages, 2)
+}
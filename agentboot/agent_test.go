@@ -82,13 +82,16 @@ func TestNewAgent(t *testing.T) {
 		Build()
 
 	expectedConfig := AgentConfig{
-		MiniModel:    nil,
-		BigModel:     nil,
-		ToolSelector: mockToolSelector,
-		SystemPrompt: "",
-		Tools:        nil,
-		MaxTokens:    2000,
-		MaxTurns:     5,
+		MiniModel:            nil,
+		BigModel:             nil,
+		ToolSelector:         mockToolSelector,
+		SystemPrompt:         "",
+		Tools:                nil,
+		MaxTokens:            2000,
+		MaxTurns:             5,
+		Temperature:          0.7,
+		MaxParallelTools:     4,
+		AnswerRepairAttempts: 2,
 	}
 
 	assert.NotNil(t, agent)
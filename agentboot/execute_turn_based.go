@@ -2,106 +2,238 @@ package agentboot
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/SaiNageswarS/agent-boot/llm"
 	"github.com/SaiNageswarS/agent-boot/memory"
-	"github.com/SaiNageswarS/agent-boot/prompts"
 	"github.com/SaiNageswarS/agent-boot/schema"
 	"github.com/SaiNageswarS/go-api-boot/logger"
 	"github.com/ollama/ollama/api"
 	"go.uber.org/zap"
 )
 
+// Final statuses reported in StreamComplete.FinalStatus describing why the tool loop ended.
+const (
+	FinalStatusNoToolsSelected = "no_tools_selected"
+	FinalStatusRepeatedTools   = "repeated_tool_calls"
+	FinalStatusMaxTurnsReached = "max_turns_reached"
+	// FinalStatusToolSelectionFailed means the tool selector failed, e.g. on a provider rate limit,
+	// so the answer is generated from the tool results gathered so far.
+	FinalStatusToolSelectionFailed = "tool_selection_failed"
+)
+
+// ErrInferenceFailed is returned by Execute when the big model fails to answer. It wraps the error
+// of the model, such as an *llm.APIError.
+var ErrInferenceFailed = errors.New("inference failed")
+
 // ExecuteTurnBased executes the agent using turn-based mode with support for native tool calling
 func (a *Agent) Execute(ctx context.Context, reporter ProgressReporter, req *schema.GenerateAnswerRequest) (*schema.StreamComplete, error) {
 	startTime := getCurrentTimeMs()
 
+	// Apply per-request limits on a copy so concurrent requests don't interfere.
+	a = a.withRequestOverrides(req)
+	a.usage = newUsageTracker()
+
 	response := &schema.StreamComplete{ToolsUsed: []string{}, Metadata: map[string]string{}}
 
 	// Load previous conversation messages
+	userID := req.Metadata[MetadataUserID]
 	conversation := &memory.Conversation{}
 	if a.config.ConversationManager != nil {
-		conversation = a.config.ConversationManager.LoadSession(ctx, req.SessionId)
+		var err error
+		conversation, err = a.config.ConversationManager.LoadUserSession(ctx, userID, req.SessionId)
+		if errors.Is(err, memory.ErrSessionNotFound) {
+			return nil, err
+		}
+		if err != nil {
+			// Continue without history rather than failing the request
+			logger.Error("Failed to load session", zap.String("session_id", req.SessionId), zap.Error(err))
+			conversation = &memory.Conversation{ID: req.SessionId, UserID: userID}
+		}
+	}
+
+	// Facts remembered from earlier sessions go ahead of the conversation
+	memories := a.recallMemories(ctx, userID, req.Question)
+	history := func() []llm.Message {
+		return slices.Concat(memories, conversation.ContextMessages())
 	}
 
 	// Add user message to conversation
+	turnStart := len(conversation.Messages)
 	conversation.AddUserMessage(req.Question)
+	record := memory.TurnRecord{Question: req.Question, StartedAt: startTime}
 
+	response.FinalStatus = FinalStatusMaxTurnsReached
+	seenToolCalls := map[string]bool{}
 	for turn := 0; turn < a.config.MaxTurns; turn++ {
 		// Step 1: Select tools using gpt-oss
-		toolCalls := a.SelectTools(ctx, reporter, conversation.Messages, turn)
-		// Run Tool Calls
-		for _, toolCall := range toolCalls {
-			toolResultContext, err := a.RunTool(ctx, reporter, req.Question, &toolCall)
-			if err != nil {
-				continue
+		toolCalls, err := a.SelectTools(ctx, reporter, history(), turn)
+		if err != nil {
+			logger.Error("Failed to select tools", zap.Int("turn", turn), zap.Error(err))
+			reporter.Send(NewStreamError(err.Error(), "tool_selection_failed"))
+			response.FinalStatus = FinalStatusToolSelectionFailed
+			reporter.Send(NewProgressUpdate(
+				schema.Stage_answer_generation_starting,
+				fmt.Sprintf("Tool selection failed in turn %d, generating answer", turn)))
+			break
+		}
+		if len(toolCalls) == 0 {
+			response.FinalStatus = FinalStatusNoToolsSelected
+			reporter.Send(NewProgressUpdate(
+				schema.Stage_answer_generation_starting,
+				fmt.Sprintf("No tools selected in turn %d, generating answer", turn)))
+			break
+		}
+
+		// Drop calls already made in earlier turns; their results are in the conversation.
+		toolCalls = dropRepeatedToolCalls(toolCalls, seenToolCalls)
+		if len(toolCalls) == 0 {
+			response.FinalStatus = FinalStatusRepeatedTools
+			reporter.Send(NewProgressUpdate(
+				schema.Stage_answer_generation_starting,
+				fmt.Sprintf("Only repeated tool calls selected in turn %d, generating answer", turn)))
+			break
+		}
+
+		// Run Tool Calls concurrently; results come back in selection order
+		conversation.AddToolCalls(toolCalls)
+		results, toolRecords := a.runTools(ctx, reporter, req.Question, toolCalls)
+		for i := range toolRecords {
+			toolRecords[i].SelectionTurn = turn
+		}
+		record.ToolCalls = append(record.ToolCalls, toolRecords...)
+
+		for i, toolResultContext := range results {
+			// Every call needs a result so providers can pair them
+			toolName := toolCalls[i].Function.Name
+			if toolResultContext == "" {
+				toolResultContext = fmt.Sprintf("Tool %q returned no result.", toolName)
 			}
 
 			// Add tool result to conversation
-			conversation.AddToolResult(toolResultContext)
+			conversation.AddToolResult(toolName, toolResultContext)
 		}
 	}
 
 	// Step 2: Run LLM with the selected tools
-	var inference strings.Builder
-	err := a.config.BigModel.GenerateInference(
-		ctx, conversation.Messages,
-		func(chunk string) error {
-			inference.WriteString(chunk)
-			reporter.Send(NewAnswerChunk(&schema.AnswerChunk{Content: chunk}))
-			return nil
-		},
-		llm.WithMaxTokens(a.config.MaxTokens),
-		llm.WithTemperature(0.7),
-		llm.WithSystemPrompt(a.config.SystemPrompt),
-	)
+	var err error
+	if a.config.AnswerSchema != nil {
+		response.Answer, err = a.generateStructuredAnswer(ctx, reporter, history(), response)
+	} else {
+		response.Answer, err = a.generateAnswer(ctx, reporter, history())
+	}
 
 	if err != nil {
 		logger.Error("Failed to run inference", zap.Error(err))
 		reporter.Send(NewStreamError(err.Error(), "inference_failed"))
 	}
 
-	response.Answer = inference.String()
-	response.ProcessingTime = getCurrentTimeMs() - startTime
+	// The big model may be a fallback or router client, so record the model that answered
+	record.Model = a.usage.lastModel(UsageRoleBigModel)
+	if record.Model == "" {
+		record.Model = a.config.BigModel.GetModel()
+	}
 
 	conversation.AddAssistantMessage(response.Answer)
+	// Summarizing folds older messages, so keep this turn for learning
+	turn := slices.Clone(conversation.Messages[turnStart:])
+
+	// Summarize now rather than when saving, so the usage of the summary is reported
+	if a.config.ConversationManager != nil {
+		summaryModel, role := a.config.summaryModel()
+		err := a.config.ConversationManager.Summarize(ctx, conversation,
+			llm.WithUsageCallback(a.usage.recorder(role, summaryModel)))
+		if err != nil {
+			logger.Error("Failed to summarize session", zap.String("session_id", conversation.ID), zap.Error(err))
+		}
+	}
+
+	response.ProcessingTime = getCurrentTimeMs() - startTime
+	a.usage.report(response, a.config.ModelPrices)
+
+	record.Answer = response.Answer
+	record.Usage = a.usage.records()
+	record.FinalStatus = response.FinalStatus
+	record.CompletedAt = startTime + response.ProcessingTime
+	if err != nil {
+		record.Error = err.Error()
+	}
+	conversation.AddTurn(record)
+
 	// Save session with assistant response
 	if a.config.ConversationManager != nil {
 		a.config.ConversationManager.SaveSession(ctx, conversation)
 	}
 
+	// The failed turn is kept in the session, but the request fails rather than completing
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInferenceFailed, err)
+	}
+
 	reporter.Send(NewStreamComplete(response))
+
+	// Learn from the turn once the client has the answer
+	a.learnMemories(ctx, userID, turn)
 	return response, nil
 }
 
-func (a *Agent) SelectTools(ctx context.Context, reporter ProgressReporter, msgs []llm.Message, turn int) []api.ToolCall {
+// generateAnswer streams the answer of the big model to the reporter and returns the full text.
+func (a *Agent) generateAnswer(ctx context.Context, reporter ProgressReporter, msgs []llm.Message) (string, error) {
+	var inference strings.Builder
+	err := a.config.BigModel.GenerateInference(
+		ctx, a.contextMessages(a.config.SystemPrompt, msgs),
+		func(chunk string) error {
+			inference.WriteString(chunk)
+			reporter.Send(NewAnswerChunk(&schema.AnswerChunk{Content: chunk}))
+			return nil
+		},
+		llm.WithMaxTokens(a.config.MaxTokens),
+		llm.WithTemperature(a.config.Temperature),
+		llm.WithSystemPrompt(a.config.SystemPrompt),
+		llm.WithStreaming(true),
+		llm.WithUsageCallback(a.usage.recorder(UsageRoleBigModel, a.config.BigModel)),
+	)
+	return inference.String(), err
+}
+
+// SelectTools asks the tool selector which tools to call for msgs in the given turn. It returns an
+// error when the prompt can't be rendered or the selector fails, rather than no tool calls.
+func (a *Agent) SelectTools(ctx context.Context, reporter ProgressReporter, msgs []llm.Message, turn int) ([]api.ToolCall, error) {
 	var toolCalls []api.ToolCall
 
 	// Render tool selection system prompt
-	systemPrompt, err := prompts.RenderToolSelectionPrompt(turn)
+	systemPrompt, err := a.config.Prompts.RenderToolSelectionPrompt(turn, a.promptVars(msgs))
 	if err != nil {
-		logger.Error("Failed to render tool selection prompt", zap.Error(err))
-		reporter.Send(NewStreamError(err.Error(), "prompt_rendering_failed"))
-		return toolCalls
+		return nil, fmt.Errorf("render tool selection prompt: %w", err)
 	}
 
 	err = a.config.ToolSelector.GenerateInferenceWithTools(
-		ctx, msgs,
+		ctx, a.contextMessages(systemPrompt, msgs),
 		func(chunk string) error { return nil }, // ignore Answer
 		func(calls []api.ToolCall) error {
 			toolCalls = append(toolCalls, calls...)
 			return nil
 		},
 		llm.WithTools(toAPITools(a.config.Tools)),
+		llm.WithToolSchemas(toolSchemas(a.config.Tools)),
 		llm.WithMaxTokens(a.config.MaxTokens),
 		llm.WithSystemPrompt(systemPrompt),
+		llm.WithUsageCallback(a.usage.recorder(UsageRoleToolSelector, a.config.ToolSelector)),
 	)
 
 	if err != nil {
-		logger.Error("Failed to select tools", zap.Error(err))
-		reporter.Send(NewStreamError(err.Error(), "tool_selection_failed"))
+		return nil, err
 	}
+	return toolCalls, nil
+}
 
-	return toolCalls
+// contextMessages fits msgs into the model context with the configured TrimPolicy.
+func (a *Agent) contextMessages(systemPrompt string, msgs []llm.Message) []llm.Message {
+	if a.config.TrimPolicy == nil {
+		return msgs
+	}
+	return a.config.TrimPolicy.Trim(systemPrompt, msgs)
 }
@@ -3,12 +3,16 @@ package agentboot
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/SaiNageswarS/agent-boot/memory"
 	"github.com/SaiNageswarS/agent-boot/schema"
 	"github.com/ollama/ollama/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // MockProgressReporter implements ProgressReporter for testing
@@ -43,6 +47,21 @@ type testLLMClient struct {
 	callCount        int
 	responses        []string
 	toolCallsPerTurn [][]api.ToolCall
+	messagesPerCall  [][]llm.Message
+	usagePerCall     llm.Usage // reported through the usage callback on every call
+}
+
+// reportUsage passes usagePerCall to the usage callback among opts, like real clients do.
+func (m *testLLMClient) reportUsage(opts []llm.LLMOption) {
+	var settings llm.LLMSettings
+	for _, opt := range opts {
+		opt(&settings)
+	}
+	usage := m.usagePerCall
+	if usage.Model == "" {
+		usage.Model = m.model
+	}
+	settings.ReportUsage(usage)
 }
 
 func (m *testLLMClient) GenerateInference(
@@ -55,11 +74,14 @@ func (m *testLLMClient) GenerateInference(
 		return errors.New(m.errorMessage)
 	}
 
+	m.messagesPerCall = append(m.messagesPerCall, messages)
+
 	response := m.response
 	if m.callCount < len(m.responses) {
 		response = m.responses[m.callCount]
 	}
 	m.callCount++
+	m.reportUsage(opts)
 
 	return callback(response)
 }
@@ -75,6 +97,8 @@ func (m *testLLMClient) GenerateInferenceWithTools(
 		return errors.New(m.errorMessage)
 	}
 
+	m.messagesPerCall = append(m.messagesPerCall, messages)
+
 	response := m.response
 	var toolCalls []api.ToolCall
 
@@ -88,6 +112,7 @@ func (m *testLLMClient) GenerateInferenceWithTools(
 	}
 
 	m.callCount++
+	m.reportUsage(opts)
 
 	if len(toolCalls) > 0 {
 		return toolCallback(toolCalls)
@@ -178,10 +203,9 @@ func TestAgentExecuteWithTools(t *testing.T) {
 					},
 				},
 			},
-			{}, // No tool calls in turn 1
-			{}, // No tool calls in turn 2
+			{}, // No tool calls in turn 1, loop exits early
 		},
-		responses: []string{"", "", "", "The answer is 4"}, // 4 responses for 4 calls
+		responses: []string{"", "", "The answer is 4"}, // 2 selections + final inference
 	}
 
 	agent := NewAgentBuilder().
@@ -209,6 +233,16 @@ func TestAgentExecuteWithTools(t *testing.T) {
 
 	// Check that events were sent
 	assert.GreaterOrEqual(t, reporter.GetEventCount(), 1)
+
+	// The second selection sees the tool call and its result as native tool messages
+	secondTurn := mockBigModel.messagesPerCall[1]
+	require.Len(t, secondTurn, 3)
+	assert.Equal(t, "assistant", secondTurn[1].Role)
+	require.Len(t, secondTurn[1].ToolCalls, 1)
+	assert.Equal(t, "calculator", secondTurn[1].ToolCalls[0].Function.Name)
+	assert.Equal(t, "tool", secondTurn[2].Role)
+	assert.Equal(t, "calculator", secondTurn[2].ToolName)
+	assert.Contains(t, secondTurn[2].Content, "2 + 2 = 4")
 }
 
 func TestAgentExecuteMaxTurns(t *testing.T) {
@@ -264,6 +298,82 @@ func TestAgentExecuteMaxTurns(t *testing.T) {
 	assert.Equal(t, 3, mockBigModel.callCount) // Should have called model maxTurns times + 1 for final inference
 }
 
+func TestAgentExecuteStopsWhenNoToolsSelected(t *testing.T) {
+	mockBigModel := &testLLMClient{
+		model:    "test-big-model",
+		response: "Direct answer",
+	}
+
+	agent := NewAgentBuilder().
+		WithBigModel(mockBigModel).
+		WithToolSelector(mockBigModel).
+		WithMaxTurns(5).
+		Build()
+
+	reporter := &MockProgressReporter{}
+
+	result, err := agent.Execute(context.Background(), reporter, &schema.GenerateAnswerRequest{Question: "Hi"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Direct answer", result.Answer)
+	assert.Equal(t, FinalStatusNoToolsSelected, result.FinalStatus)
+	assert.Equal(t, 2, mockBigModel.callCount) // 1 selection + final inference
+
+	hasAnswerStarting := false
+	for _, event := range reporter.GetEvents() {
+		if p := event.GetProgressUpdateChunk(); p != nil && p.Stage == schema.Stage_answer_generation_starting {
+			hasAnswerStarting = true
+		}
+	}
+	assert.True(t, hasAnswerStarting, "Should report answer generation starting")
+}
+
+func TestAgentExecuteStopsOnRepeatedToolCalls(t *testing.T) {
+	repeatedCall := api.ToolCall{
+		Function: api.ToolCallFunction{
+			Name:      "search",
+			Arguments: map[string]any{"query": "capital of France"},
+		},
+	}
+
+	toolRuns := 0
+	mockTool := MCPTool{
+		Tool: api.Tool{
+			Function: api.ToolFunction{
+				Name: "search",
+			},
+		},
+		Handler: func(ctx context.Context, params api.ToolCallFunctionArguments) <-chan *schema.ToolResultChunk {
+			toolRuns++
+			ch := make(chan *schema.ToolResultChunk, 1)
+			ch <- &schema.ToolResultChunk{Sentences: []string{"Paris"}}
+			close(ch)
+			return ch
+		},
+	}
+
+	mockBigModel := &testLLMClient{
+		model:            "test-big-model",
+		toolCallsPerTurn: [][]api.ToolCall{{repeatedCall}, {repeatedCall}, {repeatedCall}},
+		responses:        []string{"", "", "Paris"},
+	}
+
+	agent := NewAgentBuilder().
+		WithBigModel(mockBigModel).
+		WithToolSelector(mockBigModel).
+		WithMaxTurns(5).
+		AddTool(mockTool).
+		Build()
+
+	result, err := agent.Execute(context.Background(), &MockProgressReporter{}, &schema.GenerateAnswerRequest{Question: "Capital of France?"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Paris", result.Answer)
+	assert.Equal(t, FinalStatusRepeatedTools, result.FinalStatus)
+	assert.Equal(t, 1, toolRuns)
+	assert.Equal(t, 3, mockBigModel.callCount) // 2 selections + final inference
+}
+
 func TestAgentExecuteLLMError(t *testing.T) {
 	// Setup model that returns error
 	mockBigModel := &testLLMClient{
@@ -288,12 +398,15 @@ func TestAgentExecuteLLMError(t *testing.T) {
 	// Execute
 	result, err := agent.Execute(context.Background(), reporter, req)
 
-	// The current implementation doesn't return an error for LLM failures,
-	// it returns a result with empty answer and logs the error
-	assert.NoError(t, err)
-	assert.NotNil(t, result)
-	assert.Equal(t, "", result.Answer) // Answer should be empty due to LLM error
-	assert.GreaterOrEqual(t, result.ProcessingTime, int64(0))
+	// The error is streamed and returned, without a StreamComplete
+	assert.ErrorIs(t, err, ErrInferenceFailed)
+	assert.ErrorContains(t, err, "LLM service unavailable")
+	assert.Nil(t, result)
+	require.NotEmpty(t, reporter.events)
+	assert.NotNil(t, reporter.events[len(reporter.events)-1].GetError())
+	for _, event := range reporter.events {
+		assert.Nil(t, event.GetComplete())
+	}
 }
 
 func TestAgentExecuteWithContext(t *testing.T) {
@@ -389,9 +502,10 @@ func TestAgentSelectTools(t *testing.T) {
 	}
 
 	// Execute
-	toolCalls := agent.SelectTools(context.Background(), reporter, messages, 0)
+	toolCalls, err := agent.SelectTools(context.Background(), reporter, messages, 0)
 
 	// Assert
+	require.NoError(t, err)
 	assert.Len(t, toolCalls, 1)
 	assert.Equal(t, "test-tool", toolCalls[0].Function.Name)
 }
@@ -415,12 +529,45 @@ func TestAgentSelectToolsError(t *testing.T) {
 	}
 
 	// Execute
-	toolCalls := agent.SelectTools(context.Background(), reporter, messages, 0)
+	toolCalls, err := agent.SelectTools(context.Background(), reporter, messages, 0)
 
 	// Assert
+	assert.EqualError(t, err, "Model error")
 	assert.Empty(t, toolCalls)
 }
 
+func TestAgentExecuteToolSelectionFailed(t *testing.T) {
+	selector := &testLLMClient{model: "selector", shouldError: true, errorMessage: "API request failed with status 429"}
+	bigModel := &testLLMClient{model: "big", response: "Answer without tools"}
+	store := memory.NewInMemoryStore(0)
+
+	agent := NewAgentBuilder().
+		WithBigModel(bigModel).
+		WithToolSelector(selector).
+		WithConversationStore(store, 10).
+		Build()
+
+	reporter := &MockProgressReporter{}
+	result, err := agent.Execute(context.Background(), reporter, &schema.GenerateAnswerRequest{Question: "Hi", SessionId: "s1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Answer without tools", result.Answer)
+	assert.Equal(t, FinalStatusToolSelectionFailed, result.FinalStatus)
+
+	hasSelectionError := false
+	for _, event := range reporter.GetEvents() {
+		if e := event.GetError(); e != nil && e.ErrorCode == "tool_selection_failed" {
+			hasSelectionError = true
+		}
+	}
+	assert.True(t, hasSelectionError)
+
+	saved, err := store.Get(context.Background(), "s1")
+	require.NoError(t, err)
+	require.Len(t, saved.Turns, 1)
+	assert.Equal(t, FinalStatusToolSelectionFailed, saved.Turns[0].FinalStatus)
+}
+
 func TestAgentExecuteNilReporter(t *testing.T) {
 	mockBigModel := &testLLMClient{
 		model:    "test-big-model",
@@ -510,3 +657,108 @@ func BenchmarkAgentExecute(b *testing.B) {
 		_ = result
 	}
 }
+
+func TestExecuteWithConversationStore(t *testing.T) {
+	bigModel := &testLLMClient{model: "big", responses: []string{"The answer is 4", "You asked about 2+2"}}
+	agent := NewAgentBuilder().
+		WithBigModel(bigModel).
+		WithToolSelector(&testLLMClient{model: "selector"}).
+		WithConversationStore(memory.NewInMemoryStore(time.Hour), 10).
+		Build()
+
+	_, err := agent.Execute(context.Background(), &MockProgressReporter{}, &schema.GenerateAnswerRequest{Question: "What is 2+2?", SessionId: "s1"})
+	require.NoError(t, err)
+	_, err = agent.Execute(context.Background(), &MockProgressReporter{}, &schema.GenerateAnswerRequest{Question: "What did I ask?", SessionId: "s1"})
+	require.NoError(t, err)
+
+	// The second answer is generated with the first exchange as history
+	history := bigModel.messagesPerCall[1]
+	var contents []string
+	for _, msg := range history {
+		contents = append(contents, msg.Content)
+	}
+	assert.Contains(t, contents, "What is 2+2?")
+	assert.Contains(t, contents, "The answer is 4")
+}
+
+func TestExecuteAppliesTrimPolicy(t *testing.T) {
+	store := memory.NewInMemoryStore(0)
+	require.NoError(t, store.Save(context.Background(), memory.Conversation{ID: "s1", Messages: []llm.Message{
+		{Role: "user", Content: "Summarize this document"},
+		{Role: "assistant", Content: strings.Repeat("A very long summary. ", 200)},
+	}}))
+
+	bigModel := &testLLMClient{model: "big", response: "Hello"}
+	selector := &testLLMClient{model: "selector"}
+	agent := NewAgentBuilder().
+		WithBigModel(bigModel).
+		WithToolSelector(selector).
+		WithConversationStore(store, 10).
+		WithTrimPolicy(memory.TokenBudget{MaxTokens: 200}).
+		Build()
+
+	_, err := agent.Execute(context.Background(), &MockProgressReporter{}, &schema.GenerateAnswerRequest{Question: "Hi", SessionId: "s1"})
+	require.NoError(t, err)
+
+	// Both the tool selector and the big model only see the latest question
+	for _, calls := range [][][]llm.Message{selector.messagesPerCall, bigModel.messagesPerCall} {
+		require.Len(t, calls, 1)
+		require.Len(t, calls[0], 1)
+		assert.Equal(t, "Hi", calls[0][0].Content)
+	}
+
+	// The saved conversation keeps the full history
+	saved, err := store.Get(context.Background(), "s1")
+	require.NoError(t, err)
+	assert.Len(t, saved.Messages, 4)
+}
+
+func TestExecuteReportsConversationSummaryUsage(t *testing.T) {
+	store := memory.NewInMemoryStore(0)
+	conversation := memory.Conversation{ID: "s1"}
+	conversation.AddUserMessage("Question 1")
+	conversation.AddAssistantMessage("Answer 1")
+	require.NoError(t, store.Save(context.Background(), conversation))
+
+	miniModel := &testLLMClient{model: "mini", response: "The user asked question 1.", usagePerCall: llm.Usage{PromptTokens: 50, CompletionTokens: 10}}
+	agent := NewAgentBuilder().
+		WithBigModel(&testLLMClient{model: "big", response: "Answer 2"}).
+		WithMiniModel(miniModel).
+		WithToolSelector(&testLLMClient{model: "selector"}).
+		WithConversationStore(store, 10).
+		WithConversationSummary(1, 1).
+		Build()
+
+	result, err := agent.Execute(context.Background(), &MockProgressReporter{}, &schema.GenerateAnswerRequest{Question: "Question 2", SessionId: "s1"})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, miniModel.callCount)
+	assert.Equal(t, "60", result.Metadata["usage.mini_model.total_tokens"])
+
+	saved, err := store.Get(context.Background(), "s1")
+	require.NoError(t, err)
+	assert.Equal(t, "The user asked question 1.", saved.Summary)
+	require.Len(t, saved.Turns, 1)
+	assert.Contains(t, saved.Turns[0].Usage, memory.UsageRecord{Role: UsageRoleMiniModel, Model: "mini", PromptTokens: 50, CompletionTokens: 10})
+}
+
+func TestExecuteSendsConversationSummary(t *testing.T) {
+	store := memory.NewInMemoryStore(0)
+	require.NoError(t, store.Save(context.Background(), memory.Conversation{ID: "s1", Summary: "The user's order 1234 is late."}))
+
+	bigModel := &testLLMClient{model: "big", response: "It ships tomorrow"}
+	agent := NewAgentBuilder().
+		WithBigModel(bigModel).
+		WithToolSelector(&testLLMClient{model: "selector"}).
+		WithConversationStore(store, 10).
+		Build()
+
+	_, err := agent.Execute(context.Background(), &MockProgressReporter{}, &schema.GenerateAnswerRequest{Question: "When will it arrive?", SessionId: "s1"})
+	require.NoError(t, err)
+
+	require.Len(t, bigModel.messagesPerCall, 1)
+	msgs := bigModel.messagesPerCall[0]
+	require.Len(t, msgs, 2)
+	assert.Contains(t, msgs[0].Content, "The user's order 1234 is late.")
+	assert.Equal(t, "When will it arrive?", msgs[1].Content)
+}
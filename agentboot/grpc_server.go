@@ -0,0 +1,148 @@
+package agentboot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/SaiNageswarS/agent-boot/memory"
+	"github.com/SaiNageswarS/agent-boot/schema"
+	"github.com/SaiNageswarS/go-api-boot/logger"
+	"github.com/ollama/ollama/api"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UserIDHeader is the gRPC metadata key naming the user a call is made for. Sessions are scoped to
+// that user. The server trusts the header as sent, so a trusted proxy or interceptor must
+// authenticate the caller and set it, overwriting any value from the client.
+const UserIDHeader = "x-user-id"
+
+// GrpcServer implements schema.AgentServer by executing each request with an Agent and
+// streaming its progress to the client. The session RPCs are served from the agent's
+// ConversationManager.
+type GrpcServer struct {
+	schema.UnimplementedAgentServer
+	agent *Agent
+}
+
+// NewGrpcServer creates a gRPC service for agent, to be registered with schema.RegisterAgentServer.
+func NewGrpcServer(agent *Agent) *GrpcServer {
+	return &GrpcServer{agent: agent}
+}
+
+// Execute runs the agent for req. The request context is cancelled when the client goes away or
+// the stream can no longer be written to, which stops any LLM call or tool still running.
+func (s *GrpcServer) Execute(req *schema.GenerateAnswerRequest, stream grpc.ServerStreamingServer[schema.AgentStreamChunk]) error {
+	if strings.TrimSpace(req.GetQuestion()) == "" {
+		return status.Error(codes.InvalidArgument, "question is required")
+	}
+
+	// The user comes from the call metadata only, which a trusted proxy or interceptor must set
+	if req.Metadata == nil {
+		req.Metadata = map[string]string{}
+	}
+	req.Metadata[MetadataUserID] = userIDFromContext(stream.Context())
+
+	ctx, cancel := context.WithCancelCause(stream.Context())
+	defer cancel(nil)
+
+	reporter := &streamReporter{stream: stream, cancel: cancel}
+	_, err := s.agent.Execute(ctx, reporter, req)
+	if err == nil {
+		err = context.Cause(ctx)
+	}
+	if err != nil {
+		logger.Error("Agent execution failed", zap.String("session_id", req.GetSessionId()), zap.Error(err))
+		return toStatusError(err)
+	}
+	return nil
+}
+
+// streamReporter sends progress to a gRPC stream and cancels the request when sending fails,
+// since the client will never see the rest of the answer.
+type streamReporter struct {
+	stream grpc.ServerStreamingServer[schema.AgentStreamChunk]
+	cancel context.CancelCauseFunc
+}
+
+func (r *streamReporter) Send(event *schema.AgentStreamChunk) error {
+	if err := r.stream.Send(event); err != nil {
+		r.cancel(err)
+		return err
+	}
+	return nil
+}
+
+// userIDFromContext returns the UserIDHeader of the incoming call, or "" without one.
+func userIDFromContext(ctx context.Context) string {
+	if values := metadata.ValueFromIncomingContext(ctx, UserIDHeader); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// requireUserID returns the UserIDHeader of the incoming call, or an Unauthenticated error
+// without one, so anonymous callers can't reach the sessions of other anonymous callers.
+func requireUserID(ctx context.Context) (string, error) {
+	userID := userIDFromContext(ctx)
+	if userID == "" {
+		return "", status.Errorf(codes.Unauthenticated, "%s is required", UserIDHeader)
+	}
+	return userID, nil
+}
+
+// toStatusError converts err to a gRPC status error, keeping errors that already carry a status.
+func toStatusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, memory.ErrSessionNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, memory.ErrSessionExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, memory.ErrInvalidPageToken):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, memory.ErrSessionsNotStored):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	// Provider failures: rate limits and quotas, transient outages, then anything else from the model
+	if code, ok := providerStatusCode(err); ok {
+		switch {
+		case code == http.StatusTooManyRequests:
+			return status.Error(codes.ResourceExhausted, err.Error())
+		case llm.IsRetryableStatus(code):
+			return status.Error(codes.Unavailable, err.Error())
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+	if errors.Is(err, ErrInferenceFailed) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// providerStatusCode returns the HTTP status of a failed provider request wrapped in err.
+func providerStatusCode(err error) (int, bool) {
+	var apiErr *llm.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode, true
+	}
+	var ollamaErr api.StatusError
+	if errors.As(err, &ollamaErr) {
+		return ollamaErr.StatusCode, true
+	}
+	return 0, false
+}
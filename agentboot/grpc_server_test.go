@@ -0,0 +1,160 @@
+package agentboot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/SaiNageswarS/agent-boot/schema"
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startGrpcServer serves agent over an in-memory connection and returns a client for it.
+func startGrpcServer(t *testing.T, agent *Agent) schema.AgentClient {
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	schema.RegisterAgentServer(server, NewGrpcServer(agent))
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return schema.NewAgentClient(conn)
+}
+
+// receiveAll reads the stream until it ends, returning the chunks and the final error.
+func receiveAll(stream grpc.ServerStreamingClient[schema.AgentStreamChunk]) ([]*schema.AgentStreamChunk, error) {
+	var chunks []*schema.AgentStreamChunk
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return chunks, nil
+		}
+		if err != nil {
+			return chunks, err
+		}
+		chunks = append(chunks, chunk)
+	}
+}
+
+func TestGrpcServerExecute(t *testing.T) {
+	model := &testLLMClient{model: "test-model", response: "The answer is 4"}
+	agent := NewAgentBuilder().WithBigModel(model).WithToolSelector(model).Build()
+	client := startGrpcServer(t, agent)
+
+	stream, err := client.Execute(context.Background(), &schema.GenerateAnswerRequest{Question: "What is 2+2?"})
+	require.NoError(t, err)
+
+	chunks, err := receiveAll(stream)
+	require.NoError(t, err)
+	require.NotEmpty(t, chunks)
+
+	complete := chunks[len(chunks)-1].GetComplete()
+	require.NotNil(t, complete, "The last chunk should be StreamComplete")
+	assert.Equal(t, "The answer is 4", complete.Answer)
+}
+
+func TestGrpcServerExecuteInferenceFailure(t *testing.T) {
+	model := &testLLMClient{model: "test-model", shouldError: true, errorMessage: "overloaded"}
+	agent := NewAgentBuilder().WithBigModel(model).WithToolSelector(&testLLMClient{model: "selector"}).Build()
+	client := startGrpcServer(t, agent)
+
+	stream, err := client.Execute(context.Background(), &schema.GenerateAnswerRequest{Question: "What is 2+2?"})
+	require.NoError(t, err)
+
+	chunks, err := receiveAll(stream)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	require.NotEmpty(t, chunks)
+	assert.NotNil(t, chunks[len(chunks)-1].GetError())
+}
+
+func TestGrpcServerExecuteRequiresQuestion(t *testing.T) {
+	model := &testLLMClient{model: "test-model"}
+	client := startGrpcServer(t, NewAgentBuilder().WithBigModel(model).WithToolSelector(model).Build())
+
+	stream, err := client.Execute(context.Background(), &schema.GenerateAnswerRequest{Question: "  "})
+	require.NoError(t, err)
+
+	_, err = receiveAll(stream)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, 0, model.callCount)
+}
+
+func TestGrpcServerExecuteClientCancellation(t *testing.T) {
+	toolStarted := make(chan struct{})
+	toolCancelled := make(chan struct{})
+	slowTool := MCPTool{
+		Tool: api.Tool{Function: api.ToolFunction{Name: "slow"}},
+		Handler: func(ctx context.Context, params api.ToolCallFunctionArguments) <-chan *schema.ToolResultChunk {
+			ch := make(chan *schema.ToolResultChunk)
+			go func() {
+				defer close(ch)
+				close(toolStarted)
+				<-ctx.Done()
+				close(toolCancelled)
+			}()
+			return ch
+		},
+	}
+
+	selector := &testLLMClient{
+		model:            "selector",
+		toolCallsPerTurn: [][]api.ToolCall{{{Function: api.ToolCallFunction{Name: "slow"}}}},
+	}
+	agent := NewAgentBuilder().
+		WithBigModel(&testLLMClient{model: "big"}).
+		WithToolSelector(selector).
+		WithMaxTurns(1).
+		AddTool(slowTool).
+		Build()
+	client := startGrpcServer(t, agent)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.Execute(ctx, &schema.GenerateAnswerRequest{Question: "Run the slow tool"})
+	require.NoError(t, err)
+
+	<-toolStarted
+	cancel()
+
+	select {
+	case <-toolCancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("The tool context was not cancelled when the client went away")
+	}
+
+	_, err = receiveAll(stream)
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
+
+func TestToStatusError(t *testing.T) {
+	assert.Equal(t, codes.Canceled, status.Code(toStatusError(context.Canceled)))
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(toStatusError(context.DeadlineExceeded)))
+	assert.Equal(t, codes.Internal, status.Code(toStatusError(errors.New("boom"))))
+
+	inferenceError := func(err error) error { return fmt.Errorf("%w: %w", ErrInferenceFailed, err) }
+	assert.Equal(t, codes.ResourceExhausted, status.Code(toStatusError(inferenceError(&llm.APIError{StatusCode: 429}))))
+	assert.Equal(t, codes.Unavailable, status.Code(toStatusError(inferenceError(&llm.APIError{StatusCode: 529}))))
+	assert.Equal(t, codes.Unavailable, status.Code(toStatusError(inferenceError(api.StatusError{StatusCode: 503}))))
+	assert.Equal(t, codes.Internal, status.Code(toStatusError(inferenceError(&llm.APIError{StatusCode: 400}))))
+	assert.Equal(t, codes.Unavailable, status.Code(toStatusError(inferenceError(errors.New("connection refused")))))
+	assert.Equal(t, codes.Canceled, status.Code(toStatusError(inferenceError(context.Canceled))))
+
+	notFound := status.Error(codes.NotFound, "no such session")
+	assert.Equal(t, notFound, toStatusError(notFound))
+}
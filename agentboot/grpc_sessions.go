@@ -0,0 +1,128 @@
+package agentboot
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/SaiNageswarS/agent-boot/memory"
+	"github.com/SaiNageswarS/agent-boot/schema"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListSessions lists the sessions of the calling user, most recently updated first. Like the other
+// session RPCs, it fails with Unauthenticated for calls without a UserIDHeader.
+func (s *GrpcServer) ListSessions(ctx context.Context, req *schema.ListSessionsRequest) (*schema.ListSessionsResponse, error) {
+	userID, err := requireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, nextPageToken, err := s.conversations().ListSessions(ctx, userID, int(req.GetPageSize()), req.GetPageToken())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	resp := &schema.ListSessionsResponse{
+		Sessions:      make([]*schema.SessionSummary, len(sessions)),
+		NextPageToken: nextPageToken,
+	}
+	for i, session := range sessions {
+		resp.Sessions[i] = &schema.SessionSummary{
+			SessionId:    session.ID,
+			Title:        session.Title(),
+			MessageCount: int32(len(session.Messages)),
+			UpdatedAt:    session.UpdatedAt,
+		}
+	}
+	return resp, nil
+}
+
+// GetSession returns a session of the calling user with all its messages.
+func (s *GrpcServer) GetSession(ctx context.Context, req *schema.GetSessionRequest) (*schema.Session, error) {
+	userID, err := requireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetSessionId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "sessionId is required")
+	}
+
+	session, err := s.conversations().GetSession(ctx, userID, req.GetSessionId())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toSessionProto(session), nil
+}
+
+// DeleteSession deletes a session of the calling user.
+func (s *GrpcServer) DeleteSession(ctx context.Context, req *schema.DeleteSessionRequest) (*schema.DeleteSessionResponse, error) {
+	userID, err := requireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetSessionId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "sessionId is required")
+	}
+
+	if err := s.conversations().DeleteSession(ctx, userID, req.GetSessionId()); err != nil {
+		return nil, toStatusError(err)
+	}
+	return &schema.DeleteSessionResponse{}, nil
+}
+
+// ForkSession copies the first messages of a session of the calling user into a new session.
+func (s *GrpcServer) ForkSession(ctx context.Context, req *schema.ForkSessionRequest) (*schema.Session, error) {
+	userID, err := requireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetSessionId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "sessionId is required")
+	}
+	if req.GetMessageCount() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "messageCount must not be negative")
+	}
+
+	fork, err := s.conversations().ForkSession(ctx, userID, req.GetSessionId(), int(req.GetMessageCount()), req.GetNewSessionId())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toSessionProto(fork), nil
+}
+
+// conversations returns the agent's ConversationManager. Without one, a manager without
+// storage is returned, whose session methods fail with memory.ErrSessionsNotStored.
+func (s *GrpcServer) conversations() *memory.ConversationManager {
+	if s.agent.config.ConversationManager == nil {
+		return memory.NewConversationManager(nil, 0)
+	}
+	return s.agent.config.ConversationManager
+}
+
+func toSessionProto(session *memory.Conversation) *schema.Session {
+	messages := make([]*schema.SessionMessage, len(session.Messages))
+	for i, msg := range session.Messages {
+		messages[i] = &schema.SessionMessage{
+			Role:     msg.Role,
+			Content:  msg.Content,
+			ToolName: msg.ToolName,
+		}
+		for _, call := range msg.ToolCalls {
+			args, err := json.Marshal(call.Function.Arguments)
+			if err != nil || call.Function.Arguments == nil {
+				args = []byte("{}")
+			}
+			messages[i].ToolCalls = append(messages[i].ToolCalls, &schema.SessionToolCall{
+				Name:      call.Function.Name,
+				Arguments: string(args),
+			})
+		}
+	}
+
+	return &schema.Session{
+		SessionId: session.ID,
+		Messages:  messages,
+		UpdatedAt: session.UpdatedAt,
+	}
+}
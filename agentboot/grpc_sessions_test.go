@@ -0,0 +1,141 @@
+package agentboot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SaiNageswarS/agent-boot/internal/memorytest"
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/SaiNageswarS/agent-boot/memory"
+	"github.com/SaiNageswarS/agent-boot/schema"
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// startSessionServer serves an agent storing its sessions in collection.
+func startSessionServer(t *testing.T, collection *memorytest.Collection) schema.AgentClient {
+	model := &testLLMClient{model: "test-model", response: "The answer is 4"}
+	agent := NewAgentBuilder().
+		WithBigModel(model).
+		WithToolSelector(model).
+		WithConversationManager(collection, 10).
+		Build()
+	return startGrpcServer(t, agent)
+}
+
+func asUser(userID string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), UserIDHeader, userID)
+}
+
+func TestGrpcServerSessions(t *testing.T) {
+	collection := memorytest.NewCollection(
+		memory.Conversation{ID: "s1", UserID: "alice", UpdatedAt: 2, Messages: []llm.Message{
+			{Role: "user", Content: "What is 2+2?"},
+			{Role: "assistant", ToolCalls: []api.ToolCall{{Function: api.ToolCallFunction{Name: "add", Arguments: api.ToolCallFunctionArguments{"a": 2}}}}},
+			{Role: "tool", ToolName: "add", Content: "4"},
+			{Role: "assistant", Content: "4"},
+		}},
+		memory.Conversation{ID: "s2", UserID: "alice", UpdatedAt: 1},
+		memory.Conversation{ID: "s3", UserID: "bob", UpdatedAt: 3},
+	)
+	client := startSessionServer(t, collection)
+
+	list, err := client.ListSessions(asUser("alice"), &schema.ListSessionsRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.Sessions, 2)
+	assert.Equal(t, "s1", list.Sessions[0].SessionId)
+	assert.Equal(t, "What is 2+2?", list.Sessions[0].Title)
+	assert.Equal(t, int32(4), list.Sessions[0].MessageCount)
+	assert.Empty(t, list.NextPageToken)
+
+	session, err := client.GetSession(asUser("alice"), &schema.GetSessionRequest{SessionId: "s1"})
+	require.NoError(t, err)
+	require.Len(t, session.Messages, 4)
+	require.Len(t, session.Messages[1].ToolCalls, 1)
+	assert.Equal(t, "add", session.Messages[1].ToolCalls[0].Name)
+	assert.JSONEq(t, `{"a":2}`, session.Messages[1].ToolCalls[0].Arguments)
+	assert.Equal(t, "add", session.Messages[2].ToolName)
+
+	fork, err := client.ForkSession(asUser("alice"), &schema.ForkSessionRequest{SessionId: "s1", MessageCount: 1, NewSessionId: "s4"})
+	require.NoError(t, err)
+	assert.Equal(t, "s4", fork.SessionId)
+	assert.Len(t, fork.Messages, 1)
+
+	_, err = client.ForkSession(asUser("alice"), &schema.ForkSessionRequest{SessionId: "s1", NewSessionId: "s2"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = client.DeleteSession(asUser("alice"), &schema.DeleteSessionRequest{SessionId: "s2"})
+	require.NoError(t, err)
+	_, err = client.GetSession(asUser("alice"), &schema.GetSessionRequest{SessionId: "s2"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGrpcServerSessionsAreScopedToUser(t *testing.T) {
+	client := startSessionServer(t, memorytest.NewCollection(
+		memory.Conversation{ID: "s1", UserID: "alice"},
+		memory.Conversation{ID: "s2"},
+	))
+
+	_, err := client.GetSession(asUser("bob"), &schema.GetSessionRequest{SessionId: "s1"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.DeleteSession(asUser("bob"), &schema.DeleteSessionRequest{SessionId: "s1"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// Calls without a user can't reach unscoped sessions either
+	_, err = client.ListSessions(context.Background(), &schema.ListSessionsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.GetSession(context.Background(), &schema.GetSessionRequest{SessionId: "s2"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.DeleteSession(context.Background(), &schema.DeleteSessionRequest{SessionId: "s2"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.ForkSession(context.Background(), &schema.ForkSessionRequest{SessionId: "s2"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	stream, err := client.Execute(asUser("bob"), &schema.GenerateAnswerRequest{
+		Question:  "What did I ask?",
+		SessionId: "s1",
+		Metadata:  map[string]string{MetadataUserID: "alice"},
+	})
+	require.NoError(t, err)
+	_, err = receiveAll(stream)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGrpcServerSessionsValidation(t *testing.T) {
+	client := startSessionServer(t, memorytest.NewCollection())
+
+	_, err := client.GetSession(asUser("alice"), &schema.GetSessionRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.ForkSession(asUser("alice"), &schema.ForkSessionRequest{SessionId: "s1", MessageCount: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.ListSessions(asUser("alice"), &schema.ListSessionsRequest{PageToken: "bogus"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	model := &testLLMClient{model: "test-model"}
+	unstored := startGrpcServer(t, NewAgentBuilder().WithBigModel(model).WithToolSelector(model).Build())
+	_, err = unstored.ListSessions(asUser("alice"), &schema.ListSessionsRequest{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestGrpcServerExecuteSavesUserSession(t *testing.T) {
+	collection := memorytest.NewCollection()
+	client := startSessionServer(t, collection)
+
+	stream, err := client.Execute(asUser("alice"), &schema.GenerateAnswerRequest{Question: "What is 2+2?", SessionId: "s1"})
+	require.NoError(t, err)
+	_, err = receiveAll(stream)
+	require.NoError(t, err)
+
+	list, err := client.ListSessions(asUser("alice"), &schema.ListSessionsRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.Sessions, 1)
+	assert.Equal(t, "s1", list.Sessions[0].SessionId)
+	assert.NotZero(t, list.Sessions[0].UpdatedAt)
+}
@@ -0,0 +1,67 @@
+package agentboot
+
+import (
+	"context"
+	"time"
+
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/SaiNageswarS/go-api-boot/logger"
+	"go.uber.org/zap"
+)
+
+// recallMemories returns a message with the facts remembered about userID that are relevant to
+// question, or nothing when there are none. Memories are only kept for identified users.
+func (a *Agent) recallMemories(ctx context.Context, userID, question string) []llm.Message {
+	if a.config.LongTermMemory == nil || userID == "" {
+		return nil
+	}
+
+	facts, err := a.config.LongTermMemory.Recall(ctx, userID, question, a.config.MemoryTopK)
+	if err != nil {
+		logger.Error("Failed to recall memories", zap.String("user_id", userID), zap.Error(err))
+		return nil
+	}
+	if len(facts) == 0 {
+		return nil
+	}
+
+	contents := make([]string, len(facts))
+	for i, fact := range facts {
+		contents[i] = fact.Content
+	}
+	content, err := a.config.Prompts.RenderLongTermMemoryPrompt(contents, a.promptVars(nil))
+	if err != nil {
+		logger.Error("Failed to render memories", zap.Error(err))
+		return nil
+	}
+	return []llm.Message{{Role: "user", Content: content}}
+}
+
+// learnMemoriesTimeout bounds the background extraction of facts from a turn.
+const learnMemoriesTimeout = 2 * time.Minute
+
+// learnMemories has the mini model extract durable facts about userID from a completed turn. It
+// runs in the background, so the request doesn't wait for it and the client hanging up doesn't
+// cancel it. The request is already reported, so the usage of the extraction is logged.
+func (a *Agent) learnMemories(ctx context.Context, userID string, turn []llm.Message) {
+	if a.config.LongTermMemory == nil || a.config.MiniModel == nil || userID == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), learnMemoriesTimeout)
+	a.goBackground(func() {
+		defer cancel()
+
+		usage := newUsageTracker()
+		facts, err := a.config.LongTermMemory.Learn(ctx, a.config.MiniModel, userID, turn,
+			llm.WithUsageCallback(usage.recorder(UsageRoleMiniModel, a.config.MiniModel)))
+		if err != nil {
+			logger.Error("Failed to learn memories", zap.String("user_id", userID), zap.Error(err))
+			return
+		}
+		logger.Info("Learned memories",
+			zap.String("user_id", userID),
+			zap.Int("facts", len(facts)),
+			zap.Any("usage", usage.records()))
+	})
+}
@@ -0,0 +1,89 @@
+package agentboot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/SaiNageswarS/agent-boot/memory"
+	"github.com/SaiNageswarS/agent-boot/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteWithLongTermMemory(t *testing.T) {
+	mem := memory.NewLongTermMemory(llm.NewHashEmbedder(512))
+	miniModel := &testLLMClient{model: "mini", responses: []string{"- The user lives in Berlin", "NONE"}}
+	bigModel := &testLLMClient{model: "big", responses: []string{"Noted!", "Take the U-Bahn."}}
+	agent := NewAgentBuilder().
+		WithBigModel(bigModel).
+		WithMiniModel(miniModel).
+		WithToolSelector(&testLLMClient{model: "selector"}).
+		WithLongTermMemory(mem, 3).
+		Build()
+
+	ask := func(question, userID string) {
+		_, err := agent.Execute(context.Background(), &MockProgressReporter{}, &schema.GenerateAnswerRequest{
+			Question: question,
+			Metadata: map[string]string{MetadataUserID: userID},
+		})
+		require.NoError(t, err)
+		agent.Wait()
+	}
+
+	ask("I live in Berlin", "alice")
+	require.Len(t, mem.Index().Facts("alice"), 1)
+	require.Len(t, miniModel.messagesPerCall, 1)
+	assert.Contains(t, miniModel.messagesPerCall[0][0].Content, "User: I live in Berlin")
+	assert.Contains(t, miniModel.messagesPerCall[0][0].Content, "Assistant: Noted!")
+
+	// A later session of the same user starts with the relevant facts
+	ask("What should I see in Berlin?", "alice")
+	msgs := bigModel.messagesPerCall[1]
+	require.Len(t, msgs, 2)
+	assert.Contains(t, msgs[0].Content, "- The user lives in Berlin")
+	assert.Equal(t, "What should I see in Berlin?", msgs[1].Content)
+}
+
+func TestExecuteLongTermMemoryNeedsUser(t *testing.T) {
+	mem := memory.NewLongTermMemory(llm.NewHashEmbedder(512))
+	require.NoError(t, mem.Remember(context.Background(), "alice", []string{"The user lives in Berlin"}))
+
+	miniModel := &testLLMClient{model: "mini", response: "- The user likes trains"}
+	bigModel := &testLLMClient{model: "big", response: "Hello"}
+	agent := NewAgentBuilder().
+		WithBigModel(bigModel).
+		WithMiniModel(miniModel).
+		WithToolSelector(&testLLMClient{model: "selector"}).
+		WithLongTermMemory(mem, 3).
+		Build()
+
+	_, err := agent.Execute(context.Background(), &MockProgressReporter{}, &schema.GenerateAnswerRequest{Question: "Where does the user live?"})
+	require.NoError(t, err)
+	agent.Wait()
+
+	assert.Len(t, bigModel.messagesPerCall[0], 1, "Anonymous requests get no memories")
+	assert.Zero(t, miniModel.callCount, "Nothing is learned from anonymous requests")
+}
+
+func TestExecuteLearnsMemoriesAfterClientLeaves(t *testing.T) {
+	mem := memory.NewLongTermMemory(llm.NewHashEmbedder(512))
+	agent := NewAgentBuilder().
+		WithBigModel(&testLLMClient{model: "big", response: "Noted!"}).
+		WithMiniModel(&testLLMClient{model: "mini", response: "- The user lives in Berlin"}).
+		WithToolSelector(&testLLMClient{model: "selector"}).
+		WithLongTermMemory(mem, 3).
+		Build()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := agent.Execute(ctx, &MockProgressReporter{}, &schema.GenerateAnswerRequest{
+		Question: "I live in Berlin",
+		Metadata: map[string]string{MetadataUserID: "alice"},
+	})
+	require.NoError(t, err)
+
+	// The client hanging up once it has the answer doesn't stop the learning
+	cancel()
+	agent.Wait()
+	assert.Len(t, mem.Index().Facts("alice"), 1)
+}
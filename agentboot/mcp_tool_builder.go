@@ -24,6 +24,10 @@ func NewMCPToolBuilder(name, description string) *MCPToolBuilder {
 					Description: description,
 				},
 			},
+			InputSchema: map[string]any{
+				"type":       "object",
+				"properties": make(map[string]any, 8),
+			},
 		},
 	}
 
@@ -34,27 +38,56 @@ func NewMCPToolBuilder(name, description string) *MCPToolBuilder {
 	return b
 }
 
-func (b *MCPToolBuilder) StringParam(name, desc string, required bool) *MCPToolBuilder {
-	prop := api.ToolProperty{
-		Type:        api.PropertyType{"string"},
-		Description: desc,
-	}
+func (b *MCPToolBuilder) StringParam(name, desc string, required bool, opts ...ParamOption) *MCPToolBuilder {
+	b.setProp(name, paramSchema("string", desc, opts), required)
+	return b
+}
+
+func (b *MCPToolBuilder) StringSliceParam(name, desc string, required bool, opts ...ParamOption) *MCPToolBuilder {
+	return b.ArrayParam(name, desc, TypeSchema("string"), required, opts...)
+}
 
-	b.setProp(name, prop, required)
+func (b *MCPToolBuilder) NumberParam(name, desc string, required bool, opts ...ParamOption) *MCPToolBuilder {
+	b.setProp(name, paramSchema("number", desc, opts), required)
 	return b
 }
 
-func (b *MCPToolBuilder) StringSliceParam(name, desc string, required bool) *MCPToolBuilder {
-	prop := api.ToolProperty{
-		Type:        api.PropertyType{"array"},
-		Items:       map[string]any{"type": "string"},
-		Description: desc,
-	}
+func (b *MCPToolBuilder) IntegerParam(name, desc string, required bool, opts ...ParamOption) *MCPToolBuilder {
+	b.setProp(name, paramSchema("integer", desc, opts), required)
+	return b
+}
+
+func (b *MCPToolBuilder) BooleanParam(name, desc string, required bool, opts ...ParamOption) *MCPToolBuilder {
+	b.setProp(name, paramSchema("boolean", desc, opts), required)
+	return b
+}
+
+// EnumParam adds a string parameter restricted to values.
+func (b *MCPToolBuilder) EnumParam(name, desc string, values []string, required bool, opts ...ParamOption) *MCPToolBuilder {
+	b.setProp(name, paramSchema("string", desc, append([]ParamOption{ParamEnum(values...)}, opts...)), required)
+	return b
+}
 
-	b.setProp(name, prop, required)
+// ArrayParam adds an array parameter whose elements match items, e.g. TypeSchema("number"),
+// ArraySchema(...) for nested arrays or NewObjectSchema()...Build() for arrays of objects.
+func (b *MCPToolBuilder) ArrayParam(name, desc string, items map[string]any, required bool, opts ...ParamOption) *MCPToolBuilder {
+	schema := paramSchema("array", desc, opts)
+	schema["items"] = items
+	b.setProp(name, schema, required)
 	return b
 }
 
+// ObjectParam adds a nested object parameter with its own properties and required list.
+func (b *MCPToolBuilder) ObjectParam(name, desc string, obj *ObjectSchemaBuilder, required bool, opts ...ParamOption) *MCPToolBuilder {
+	b.setProp(name, obj.schema(desc, opts), required)
+	return b
+}
+
+// ObjectSliceParam adds an array parameter whose elements are objects defined by obj.
+func (b *MCPToolBuilder) ObjectSliceParam(name, desc string, obj *ObjectSchemaBuilder, required bool, opts ...ParamOption) *MCPToolBuilder {
+	return b.ArrayParam(name, desc, obj.Build(), required, opts...)
+}
+
 func (b *MCPToolBuilder) Summarize(enabled bool) *MCPToolBuilder {
 	b.tool.SummarizeContext = enabled
 	return b
@@ -69,13 +102,14 @@ func (b *MCPToolBuilder) Build() MCPTool {
 	return b.tool
 }
 
-func (b *MCPToolBuilder) setProp(name string, p api.ToolProperty, required bool) {
-	props := b.tool.Function.Parameters.Properties
-	props[name] = p
+func (b *MCPToolBuilder) setProp(name string, schema map[string]any, required bool) {
+	b.tool.Function.Parameters.Properties[name] = toToolProperty(schema)
+	b.tool.InputSchema["properties"].(map[string]any)[name] = schema
 	if required {
 		req := b.tool.Function.Parameters.Required
 		if !slices.Contains(req, name) {
 			b.tool.Function.Parameters.Required = append(req, name)
+			b.tool.InputSchema["required"] = slices.Clone(b.tool.Function.Parameters.Required)
 		}
 	}
 }
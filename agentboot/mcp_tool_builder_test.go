@@ -167,6 +167,117 @@ func TestMCPToolBuilderDuplicateRequired(t *testing.T) {
 	assert.Equal(t, 1, count, "Required parameter should not be duplicated")
 }
 
+func TestMCPToolBuilderNumericParams(t *testing.T) {
+	tool := NewMCPToolBuilder("search", "Search").
+		NumberParam("threshold", "Score threshold", false, ParamMin(0), ParamMax(1)).
+		IntegerParam("limit", "Result limit", true, ParamMin(1), ParamMax(50), ParamDefault(10)).
+		Build()
+
+	threshold := tool.Function.Parameters.Properties["threshold"]
+	assert.Equal(t, api.PropertyType{"number"}, threshold.Type)
+	assert.Equal(t, "Score threshold Minimum: 0. Maximum: 1.", threshold.Description)
+
+	limit := tool.Function.Parameters.Properties["limit"]
+	assert.Equal(t, api.PropertyType{"integer"}, limit.Type)
+	assert.Equal(t, "Result limit Minimum: 1. Maximum: 50. Default: 10.", limit.Description)
+	assert.Equal(t, []string{"limit"}, tool.Function.Parameters.Required)
+
+	props := schemaProperties(tool.InputSchema)
+	assert.Equal(t, map[string]any{
+		"type":        "integer",
+		"description": "Result limit",
+		"minimum":     1.0,
+		"maximum":     50.0,
+		"default":     10,
+	}, props["limit"])
+	assert.Equal(t, []string{"limit"}, schemaRequired(tool.InputSchema))
+}
+
+func TestMCPToolBuilderBooleanAndEnumParams(t *testing.T) {
+	tool := NewMCPToolBuilder("search", "Search").
+		BooleanParam("exact", "Exact match", false).
+		EnumParam("sort", "Sort order", []string{"asc", "desc"}, true).
+		Build()
+
+	exact := tool.Function.Parameters.Properties["exact"]
+	assert.Equal(t, api.PropertyType{"boolean"}, exact.Type)
+	assert.Equal(t, "Exact match", exact.Description)
+
+	sort := tool.Function.Parameters.Properties["sort"]
+	assert.Equal(t, api.PropertyType{"string"}, sort.Type)
+	assert.Equal(t, []any{"asc", "desc"}, sort.Enum)
+	assert.Contains(t, tool.Function.Parameters.Required, "sort")
+}
+
+func TestMCPToolBuilderObjectParams(t *testing.T) {
+	filter := NewObjectSchema().
+		StringParam("field", "Field name", true).
+		EnumParam("op", "Operator", []string{"eq", "gt", "lt"}, true).
+		NumberParam("value", "Value to compare", false)
+
+	tool := NewMCPToolBuilder("query", "Query records").
+		ObjectParam("range", "Date range", NewObjectSchema().
+			StringParam("from", "Start date", true).
+			StringParam("to", "End date", false), false).
+		ObjectSliceParam("filters", "Filters to apply", filter, false).
+		ArrayParam("matrix", "Matrix of weights", ArraySchema(TypeSchema("number")), false).
+		Build()
+
+	rangeProp := tool.Function.Parameters.Properties["range"]
+	assert.Equal(t, api.PropertyType{"object"}, rangeProp.Type)
+	assert.Equal(t, "Date range Fields: from (string, required), to (string).", rangeProp.Description)
+
+	filters := tool.Function.Parameters.Properties["filters"]
+	assert.Equal(t, api.PropertyType{"array"}, filters.Type)
+	items := filters.Items.(map[string]any)
+	assert.Equal(t, "object", items["type"])
+	assert.Equal(t, []string{"field", "op"}, items["required"])
+	assert.Contains(t, items["properties"], "value")
+
+	matrix := tool.Function.Parameters.Properties["matrix"]
+	assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"type": "number"}}, matrix.Items)
+
+	rangeSchema := schemaProperties(tool.InputSchema)["range"].(map[string]any)
+	assert.Equal(t, []string{"from"}, rangeSchema["required"])
+}
+
+func TestObjectSchemaBuilderBuildsIndependentSchemas(t *testing.T) {
+	point := NewObjectSchema().NumberParam("x", "X", true)
+	tool := NewMCPToolBuilder("plot", "Plot points").
+		ObjectSliceParam("points", "Points", point, true, ParamDefault([]any{})).
+		Build()
+
+	// Extending the builder afterwards leaves the built schema alone
+	point.NumberParam("y", "Y", true)
+	second := point.Build()
+
+	points := schemaProperties(tool.InputSchema)["points"].(map[string]any)
+	assert.Equal(t, []any{}, points["default"])
+	assert.Len(t, schemaProperties(points["items"].(map[string]any)), 1)
+	assert.Len(t, schemaProperties(second), 2)
+}
+
+func TestMCPToolBuilderSchemaValidation(t *testing.T) {
+	tool := NewMCPToolBuilder("search", "Search").
+		StringParam("query", "Query", true).
+		IntegerParam("limit", "Result limit", false, ParamMin(1), ParamMax(50), ParamDefault(10)).
+		ObjectParam("range", "Date range", NewObjectSchema().StringParam("from", "Start date", true), false).
+		Build()
+
+	args, err := validateToolArguments(tool.inputSchema(), api.ToolCallFunctionArguments{"query": "go"})
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, args["limit"])
+
+	_, err = validateToolArguments(tool.inputSchema(), api.ToolCallFunctionArguments{"query": "go", "limit": 100})
+	assert.ErrorContains(t, err, `argument "limit": 100 is greater than the maximum 50`)
+
+	_, err = validateToolArguments(tool.inputSchema(), api.ToolCallFunctionArguments{"query": "go", "limit": 0})
+	assert.ErrorContains(t, err, `argument "limit": 0 is less than the minimum 1`)
+
+	_, err = validateToolArguments(tool.inputSchema(), api.ToolCallFunctionArguments{"query": "go", "range": map[string]any{}})
+	assert.ErrorContains(t, err, `missing required argument "range.from"`)
+}
+
 // Test ToolResultChunkBuilder
 
 func TestNewToolResultChunk(t *testing.T) {
@@ -0,0 +1,190 @@
+package agentboot
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/ollama/ollama/api"
+)
+
+// ParamOption customizes the JSON schema of a single tool parameter.
+type ParamOption func(schema map[string]any)
+
+// ParamMin sets the inclusive minimum of a number or integer parameter.
+func ParamMin(min float64) ParamOption {
+	return func(schema map[string]any) { schema["minimum"] = min }
+}
+
+// ParamMax sets the inclusive maximum of a number or integer parameter.
+func ParamMax(max float64) ParamOption {
+	return func(schema map[string]any) { schema["maximum"] = max }
+}
+
+// ParamDefault sets the value used when an optional parameter is omitted by the model.
+func ParamDefault(value any) ParamOption {
+	return func(schema map[string]any) { schema["default"] = value }
+}
+
+// ParamEnum restricts a parameter to the given values.
+func ParamEnum(values ...string) ParamOption {
+	return func(schema map[string]any) {
+		enum := make([]any, len(values))
+		for i, v := range values {
+			enum[i] = v
+		}
+		schema["enum"] = enum
+	}
+}
+
+// TypeSchema returns the schema of a value of the given JSON type, for use as array items.
+func TypeSchema(typ string, opts ...ParamOption) map[string]any {
+	return paramSchema(typ, "", opts)
+}
+
+// ArraySchema returns the schema of an array whose elements match items, for nested arrays.
+func ArraySchema(items map[string]any, opts ...ParamOption) map[string]any {
+	schema := paramSchema("array", "", opts)
+	schema["items"] = items
+	return schema
+}
+
+// ObjectSchemaBuilder defines the properties of a nested object parameter.
+type ObjectSchemaBuilder struct {
+	properties map[string]any
+	required   []string
+}
+
+func NewObjectSchema() *ObjectSchemaBuilder {
+	return &ObjectSchemaBuilder{properties: make(map[string]any, 8)}
+}
+
+func (o *ObjectSchemaBuilder) StringParam(name, desc string, required bool, opts ...ParamOption) *ObjectSchemaBuilder {
+	return o.Param(name, paramSchema("string", desc, opts), required)
+}
+
+func (o *ObjectSchemaBuilder) NumberParam(name, desc string, required bool, opts ...ParamOption) *ObjectSchemaBuilder {
+	return o.Param(name, paramSchema("number", desc, opts), required)
+}
+
+func (o *ObjectSchemaBuilder) IntegerParam(name, desc string, required bool, opts ...ParamOption) *ObjectSchemaBuilder {
+	return o.Param(name, paramSchema("integer", desc, opts), required)
+}
+
+func (o *ObjectSchemaBuilder) BooleanParam(name, desc string, required bool, opts ...ParamOption) *ObjectSchemaBuilder {
+	return o.Param(name, paramSchema("boolean", desc, opts), required)
+}
+
+func (o *ObjectSchemaBuilder) EnumParam(name, desc string, values []string, required bool, opts ...ParamOption) *ObjectSchemaBuilder {
+	return o.Param(name, paramSchema("string", desc, append([]ParamOption{ParamEnum(values...)}, opts...)), required)
+}
+
+func (o *ObjectSchemaBuilder) ArrayParam(name, desc string, items map[string]any, required bool, opts ...ParamOption) *ObjectSchemaBuilder {
+	schema := ArraySchema(items, opts...)
+	schema["description"] = desc
+	return o.Param(name, schema, required)
+}
+
+func (o *ObjectSchemaBuilder) ObjectParam(name, desc string, obj *ObjectSchemaBuilder, required bool, opts ...ParamOption) *ObjectSchemaBuilder {
+	return o.Param(name, obj.schema(desc, opts), required)
+}
+
+// Param adds a property with an arbitrary JSON schema.
+func (o *ObjectSchemaBuilder) Param(name string, schema map[string]any, required bool) *ObjectSchemaBuilder {
+	o.properties[name] = schema
+	if required && !slices.Contains(o.required, name) {
+		o.required = append(o.required, name)
+	}
+	return o
+}
+
+// Build returns the JSON schema of the object. Each call returns a new schema, so the builder can
+// be extended afterwards without changing schemas already built.
+func (o *ObjectSchemaBuilder) Build() map[string]any {
+	schema := map[string]any{
+		"type":       "object",
+		"properties": maps.Clone(o.properties),
+	}
+	if len(o.required) > 0 {
+		schema["required"] = slices.Clone(o.required)
+	}
+	return schema
+}
+
+// schema returns the schema of the object as a parameter described by desc.
+func (o *ObjectSchemaBuilder) schema(desc string, opts []ParamOption) map[string]any {
+	schema := o.Build()
+	schema["description"] = desc
+	for _, opt := range opts {
+		opt(schema)
+	}
+	return schema
+}
+
+func paramSchema(typ, desc string, opts []ParamOption) map[string]any {
+	schema := map[string]any{"type": typ}
+	if desc != "" {
+		schema["description"] = desc
+	}
+	for _, opt := range opts {
+		opt(schema)
+	}
+	return schema
+}
+
+// toToolProperty converts a parameter schema to the api.ToolProperty sent to Ollama. api.ToolProperty
+// has no fields for bounds, defaults or object properties, so those are described in the property
+// description for the model. OpenAI and Anthropic get the full schema from MCPTool.InputSchema.
+func toToolProperty(schema map[string]any) api.ToolProperty {
+	desc, _ := schema["description"].(string)
+	if constraints := describeConstraints(schema); constraints != "" {
+		desc = strings.TrimSpace(desc + " " + constraints)
+	}
+
+	prop := api.ToolProperty{
+		Type:        api.PropertyType(schemaTypes(schema)),
+		Description: desc,
+		Items:       schema["items"],
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		prop.Enum = enum
+	}
+	return prop
+}
+
+// describeConstraints summarizes the parts of a schema that api.ToolProperty cannot carry.
+func describeConstraints(schema map[string]any) string {
+	var parts []string
+	if v, ok := schema["minimum"]; ok {
+		parts = append(parts, fmt.Sprintf("Minimum: %v.", v))
+	}
+	if v, ok := schema["maximum"]; ok {
+		parts = append(parts, fmt.Sprintf("Maximum: %v.", v))
+	}
+	if v, ok := schema["default"]; ok {
+		parts = append(parts, fmt.Sprintf("Default: %v.", v))
+	}
+
+	if props := schemaProperties(schema); len(props) > 0 {
+		required := schemaRequired(schema)
+		names := make([]string, 0, len(props))
+		for name := range props {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		fields := make([]string, len(names))
+		for i, name := range names {
+			prop, _ := props[name].(map[string]any)
+			field := fmt.Sprintf("%s (%s", name, strings.Join(schemaTypes(prop), "|"))
+			if slices.Contains(required, name) {
+				field += ", required"
+			}
+			fields[i] = field + ")"
+		}
+		parts = append(parts, "Fields: "+strings.Join(fields, ", ")+".")
+	}
+
+	return strings.Join(parts, " ")
+}
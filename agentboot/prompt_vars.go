@@ -0,0 +1,37 @@
+package agentboot
+
+import (
+	"encoding/json"
+
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/SaiNageswarS/agent-boot/prompts"
+)
+
+// promptVars returns the variables given to prompt templates: the tools of the agent, the
+// metadata of the request and the tool calls made since the latest user message in msgs.
+func (a *Agent) promptVars(msgs []llm.Message) prompts.Vars {
+	vars := prompts.Vars{Metadata: a.metadata}
+
+	for _, tool := range a.config.Tools {
+		vars.Tools = append(vars.Tools, prompts.ToolInfo{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description,
+		})
+	}
+
+	start := len(msgs)
+	for start > 0 && !(msgs[start-1].Role == "user" && !msgs[start-1].IsToolResult) {
+		start--
+	}
+	for _, msg := range msgs[start:] {
+		for _, call := range msg.ToolCalls {
+			args, _ := json.Marshal(call.Function.Arguments)
+			vars.PreviousToolCalls = append(vars.PreviousToolCalls, prompts.ToolCallInfo{
+				Name:      call.Function.Name,
+				Arguments: string(args),
+			})
+		}
+	}
+
+	return vars
+}
@@ -0,0 +1,67 @@
+package agentboot
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/SaiNageswarS/agent-boot/memory"
+	"github.com/SaiNageswarS/agent-boot/prompts"
+	"github.com/SaiNageswarS/agent-boot/schema"
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteWithPromptRegistry(t *testing.T) {
+	registry, err := prompts.NewRegistry(fstest.MapFS{
+		"long_term_memory.md": {Data: []byte(
+			"Known about {{.Metadata.user_id}}: {{range .Facts}}{{.}}{{end}}. Tools: {{range .Tools}}{{.Name}}{{end}}")},
+	})
+	require.NoError(t, err)
+
+	mem := memory.NewLongTermMemory(llm.NewHashEmbedder(512))
+	require.NoError(t, mem.Remember(context.Background(), "alice", []string{"The user lives in Berlin"}))
+
+	bigModel := &testLLMClient{model: "big", response: "Visit the Pergamon Museum."}
+	agent := NewAgentBuilder().
+		WithBigModel(bigModel).
+		WithToolSelector(&testLLMClient{model: "selector"}).
+		AddTool(NewMCPToolBuilder("search", "Searches the web").Build()).
+		WithLongTermMemory(mem, 3).
+		WithPrompts(registry).
+		Build()
+
+	_, err = agent.Execute(context.Background(), &MockProgressReporter{}, &schema.GenerateAnswerRequest{
+		Question: "What should I see in Berlin?",
+		Metadata: map[string]string{MetadataUserID: "alice"},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, bigModel.messagesPerCall, 1)
+	assert.Equal(t, "Known about alice: The user lives in Berlin. Tools: search", bigModel.messagesPerCall[0][0].Content)
+}
+
+func TestPromptVarsPreviousToolCalls(t *testing.T) {
+	agent := &Agent{metadata: map[string]string{MetadataUserID: "alice"}}
+	call := func(name string, args api.ToolCallFunctionArguments) api.ToolCall {
+		return api.ToolCall{Function: api.ToolCallFunction{Name: name, Arguments: args}}
+	}
+
+	vars := agent.promptVars([]llm.Message{
+		{Role: "user", Content: "What is the weather?"},
+		{Role: "assistant", ToolCalls: []api.ToolCall{call("weather", api.ToolCallFunctionArguments{"city": "Paris"})}},
+		{Role: "user", Content: "And in Berlin?"},
+		{Role: "assistant", ToolCalls: []api.ToolCall{call("weather", api.ToolCallFunctionArguments{"city": "Berlin"})}},
+		{Role: "user", Content: "sunny", IsToolResult: true},
+		{Role: "assistant", ToolCalls: []api.ToolCall{call("forecast", nil)}},
+	})
+
+	// Only calls made for the latest question count
+	assert.Equal(t, []prompts.ToolCallInfo{
+		{Name: "weather", Arguments: `{"city":"Berlin"}`},
+		{Name: "forecast", Arguments: "null"},
+	}, vars.PreviousToolCalls)
+	assert.Equal(t, "alice", vars.Metadata[MetadataUserID])
+}
@@ -0,0 +1,66 @@
+package agentboot
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/SaiNageswarS/agent-boot/schema"
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentExecuteReplaysRecordedRun(t *testing.T) {
+	dir := t.TempDir()
+	selectorCassette := filepath.Join(dir, "selector.json")
+	bigModelCassette := filepath.Join(dir, "big_model.json")
+
+	calculator := NewMCPToolBuilder("calculator", "Evaluate an expression").
+		StringParam("expression", "Expression to evaluate", true).
+		WithHandler(func(ctx context.Context, params api.ToolCallFunctionArguments) <-chan *schema.ToolResultChunk {
+			ch := make(chan *schema.ToolResultChunk, 1)
+			ch <- NewToolResultChunk().Sentences("Result: 4").Build()
+			close(ch)
+			return ch
+		}).
+		Build()
+
+	newAgent := func(selector, bigModel llm.LLMClient) *Agent {
+		return NewAgentBuilder().
+			WithToolSelector(selector).
+			WithBigModel(bigModel).
+			AddTool(calculator).
+			Build()
+	}
+	req := &schema.GenerateAnswerRequest{Question: "What is 2+2?"}
+
+	// Record a run against the live clients
+	selector := &testLLMClient{
+		model:            "selector",
+		toolCallsPerTurn: [][]api.ToolCall{{{Function: api.ToolCallFunction{Name: "calculator", Arguments: api.ToolCallFunctionArguments{"expression": "2+2"}}}}},
+	}
+	bigModel := &testLLMClient{model: "big", response: "2+2 is 4"}
+
+	recorded, err := newAgent(
+		llm.NewRecordingClient(selector, selectorCassette),
+		llm.NewRecordingClient(bigModel, bigModelCassette),
+	).Execute(context.Background(), &MockProgressReporter{}, req)
+	require.NoError(t, err)
+
+	// Replay it offline
+	replaySelector, err := llm.NewReplayClient(selectorCassette)
+	require.NoError(t, err)
+	replayBigModel, err := llm.NewReplayClient(bigModelCassette)
+	require.NoError(t, err)
+
+	replayed, err := newAgent(replaySelector, replayBigModel).Execute(context.Background(), &MockProgressReporter{}, req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2+2 is 4", replayed.Answer)
+	assert.Equal(t, recorded.Answer, replayed.Answer)
+	assert.Equal(t, recorded.FinalStatus, replayed.FinalStatus)
+	assert.Empty(t, replaySelector.Unmatched())
+	assert.Empty(t, replayBigModel.Unmatched())
+}
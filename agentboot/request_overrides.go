@@ -0,0 +1,86 @@
+package agentboot
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/SaiNageswarS/agent-boot/schema"
+	"github.com/SaiNageswarS/go-api-boot/logger"
+	"go.uber.org/zap"
+)
+
+// Metadata keys read from GenerateAnswerRequest.Metadata to override agent settings per request.
+// Overrides can only tighten the agent's configured limits, never raise them.
+const (
+	MetadataMaxTokens    = "max_tokens"    // integer, capped at AgentConfig.MaxTokens
+	MetadataTemperature  = "temperature"   // float, clamped to [0, AgentConfig.Temperature]
+	MetadataAllowedTools = "allowed_tools" // comma-separated tool names, subset of AgentConfig.Tools
+	MetadataAnswerSchema = "answer_schema" // JSON schema object, replaces AgentConfig.AnswerSchema
+)
+
+// MetadataUserID names the user a request is made for. Sessions are saved for that user and
+// can't be loaded by others. GrpcServer sets it from the UserIDHeader of the call.
+const MetadataUserID = "user_id"
+
+// withRequestOverrides returns a copy of the agent whose config reflects the per-request
+// limits in req. The receiver is not modified, so one agent can serve requests with
+// different limits concurrently.
+func (a *Agent) withRequestOverrides(req *schema.GenerateAnswerRequest) *Agent {
+	config := a.config
+
+	if req.MaxIterations > 0 && int(req.MaxIterations) < config.MaxTurns {
+		config.MaxTurns = int(req.MaxIterations)
+	}
+
+	if v, ok := req.Metadata[MetadataMaxTokens]; ok {
+		maxTokens, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || maxTokens <= 0 {
+			logger.Error("Ignoring invalid max_tokens override", zap.String("value", v))
+		} else if maxTokens < config.MaxTokens {
+			config.MaxTokens = maxTokens
+		}
+	}
+
+	if v, ok := req.Metadata[MetadataTemperature]; ok {
+		temperature, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			logger.Error("Ignoring invalid temperature override", zap.String("value", v))
+		} else {
+			config.Temperature = min(max(temperature, 0), config.Temperature)
+		}
+	}
+
+	if v, ok := req.Metadata[MetadataAllowedTools]; ok {
+		config.Tools = filterToolsByName(config.Tools, strings.Split(v, ","))
+	}
+
+	if v, ok := req.Metadata[MetadataAnswerSchema]; ok {
+		var answerSchema map[string]any
+		if err := json.Unmarshal([]byte(v), &answerSchema); err != nil || answerSchema == nil {
+			logger.Error("Ignoring invalid answer_schema override", zap.String("value", v))
+		} else {
+			config.AnswerSchema = answerSchema
+		}
+	}
+
+	return &Agent{config: config, metadata: req.Metadata, background: a.background}
+}
+
+// filterToolsByName returns the tools whose function name is in names, preserving tool order.
+func filterToolsByName(tools []MCPTool, names []string) []MCPTool {
+	allowed := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			allowed[name] = true
+		}
+	}
+
+	filtered := make([]MCPTool, 0, len(tools))
+	for _, tool := range tools {
+		if allowed[tool.Function.Name] {
+			filtered = append(filtered, tool)
+		}
+	}
+	return filtered
+}
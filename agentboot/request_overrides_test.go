@@ -0,0 +1,160 @@
+package agentboot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SaiNageswarS/agent-boot/schema"
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOverrideTestAgent() *Agent {
+	return NewAgentBuilder().
+		WithToolSelector(&mockLLMClient{model: "selector"}).
+		WithMaxTurns(5).
+		WithMaxTokens(2000).
+		AddTool(MCPTool{Tool: api.Tool{Function: api.ToolFunction{Name: "search"}}}).
+		AddTool(MCPTool{Tool: api.Tool{Function: api.ToolFunction{Name: "calculator"}}}).
+		AddTool(MCPTool{Tool: api.Tool{Function: api.ToolFunction{Name: "database"}}}).
+		Build()
+}
+
+func TestWithRequestOverridesDefaults(t *testing.T) {
+	agent := newOverrideTestAgent()
+
+	overridden := agent.withRequestOverrides(&schema.GenerateAnswerRequest{Question: "q"})
+
+	assert.Equal(t, agent.config.MaxTurns, overridden.config.MaxTurns)
+	assert.Equal(t, agent.config.MaxTokens, overridden.config.MaxTokens)
+	assert.Equal(t, agent.config.Temperature, overridden.config.Temperature)
+	assert.Len(t, overridden.config.Tools, 3)
+}
+
+func TestWithRequestOverridesTightensLimits(t *testing.T) {
+	agent := newOverrideTestAgent()
+
+	overridden := agent.withRequestOverrides(&schema.GenerateAnswerRequest{
+		MaxIterations: 2,
+		Metadata: map[string]string{
+			MetadataMaxTokens:    "500",
+			MetadataTemperature:  "0.1",
+			MetadataAllowedTools: "database, search, unknown",
+		},
+	})
+
+	assert.Equal(t, 2, overridden.config.MaxTurns)
+	assert.Equal(t, 500, overridden.config.MaxTokens)
+	assert.Equal(t, 0.1, overridden.config.Temperature)
+	assert.Len(t, overridden.config.Tools, 2)
+	assert.Equal(t, "search", overridden.config.Tools[0].Function.Name)
+	assert.Equal(t, "database", overridden.config.Tools[1].Function.Name)
+
+	// Original agent is untouched
+	assert.Equal(t, 5, agent.config.MaxTurns)
+	assert.Equal(t, 2000, agent.config.MaxTokens)
+	assert.Len(t, agent.config.Tools, 3)
+}
+
+func TestWithRequestOverridesCannotRaiseLimits(t *testing.T) {
+	agent := newOverrideTestAgent()
+
+	overridden := agent.withRequestOverrides(&schema.GenerateAnswerRequest{
+		MaxIterations: 50,
+		Metadata: map[string]string{
+			MetadataMaxTokens:   "100000",
+			MetadataTemperature: "3.5",
+		},
+	})
+
+	assert.Equal(t, 5, overridden.config.MaxTurns)
+	assert.Equal(t, 2000, overridden.config.MaxTokens)
+	assert.Equal(t, 0.7, overridden.config.Temperature)
+
+	// Negative temperatures are raised to 0
+	overridden = agent.withRequestOverrides(&schema.GenerateAnswerRequest{
+		Metadata: map[string]string{MetadataTemperature: "-1"},
+	})
+	assert.Equal(t, 0.0, overridden.config.Temperature)
+}
+
+func TestWithRequestOverridesTemperatureFollowsAgent(t *testing.T) {
+	agent := NewAgentBuilder().
+		WithToolSelector(&mockLLMClient{model: "selector"}).
+		WithTemperature(1.5).
+		Build()
+
+	overridden := agent.withRequestOverrides(&schema.GenerateAnswerRequest{
+		Metadata: map[string]string{MetadataTemperature: "1.2"},
+	})
+
+	assert.Equal(t, 1.2, overridden.config.Temperature)
+}
+
+func TestWithRequestOverridesIgnoresInvalidValues(t *testing.T) {
+	agent := newOverrideTestAgent()
+
+	overridden := agent.withRequestOverrides(&schema.GenerateAnswerRequest{
+		Metadata: map[string]string{
+			MetadataMaxTokens:   "lots",
+			MetadataTemperature: "warm",
+		},
+	})
+
+	assert.Equal(t, 2000, overridden.config.MaxTokens)
+	assert.Equal(t, 0.7, overridden.config.Temperature)
+}
+
+func TestAgentExecuteHonorsMaxIterations(t *testing.T) {
+	endlessCall := func(query string) []api.ToolCall {
+		return []api.ToolCall{{Function: api.ToolCallFunction{Name: "search", Arguments: map[string]any{"query": query}}}}
+	}
+
+	mockBigModel := &testLLMClient{
+		model:            "test-big-model",
+		toolCallsPerTurn: [][]api.ToolCall{endlessCall("a"), endlessCall("b"), endlessCall("c")},
+		responses:        []string{"", "Quick answer"},
+	}
+
+	search := MCPTool{
+		Tool: api.Tool{Function: api.ToolFunction{Name: "search"}},
+		Handler: func(ctx context.Context, params api.ToolCallFunctionArguments) <-chan *schema.ToolResultChunk {
+			ch := make(chan *schema.ToolResultChunk, 1)
+			ch <- &schema.ToolResultChunk{Sentences: []string{"result"}}
+			close(ch)
+			return ch
+		},
+	}
+
+	agent := NewAgentBuilder().
+		WithBigModel(mockBigModel).
+		WithToolSelector(mockBigModel).
+		WithMaxTurns(5).
+		AddTool(search).
+		Build()
+
+	result, err := agent.Execute(context.Background(), &MockProgressReporter{}, &schema.GenerateAnswerRequest{
+		Question:      "Quick question",
+		MaxIterations: 1,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Quick answer", result.Answer)
+	assert.Equal(t, FinalStatusMaxTurnsReached, result.FinalStatus)
+	assert.Equal(t, 2, mockBigModel.callCount) // 1 selection + final inference
+	assert.Equal(t, 5, agent.config.MaxTurns)
+}
+
+func TestWithRequestOverridesAnswerSchema(t *testing.T) {
+	agent := newOverrideTestAgent()
+
+	overridden := agent.withRequestOverrides(&schema.GenerateAnswerRequest{
+		Metadata: map[string]string{MetadataAnswerSchema: `{"type": "object", "required": ["city"]}`},
+	})
+	assert.Equal(t, map[string]any{"type": "object", "required": []any{"city"}}, overridden.config.AnswerSchema)
+
+	invalid := agent.withRequestOverrides(&schema.GenerateAnswerRequest{
+		Metadata: map[string]string{MetadataAnswerSchema: "not json"},
+	})
+	assert.Nil(t, invalid.config.AnswerSchema)
+}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/SaiNageswarS/agent-boot/memory"
 	"github.com/SaiNageswarS/agent-boot/schema"
 	"github.com/SaiNageswarS/go-api-boot/logger"
 	"github.com/ollama/ollama/api"
@@ -13,22 +16,63 @@ import (
 )
 
 func (a *Agent) RunTool(ctx context.Context, reporter ProgressReporter, query string, selection *api.ToolCall) (string, error) {
+	return a.runTool(ctx, reporter, query, selection, &memory.ToolCallRecord{})
+}
+
+// runTool runs a tool call like RunTool, recording its arguments, results, failures and latency.
+func (a *Agent) runTool(ctx context.Context, reporter ProgressReporter, query string, selection *api.ToolCall, record *memory.ToolCallRecord) (string, error) {
+	start := time.Now()
+	record.Name = selection.Function.Name
+	record.Arguments = selection.Function.Arguments
+	record.StartedAt = start.UnixMilli()
+	defer func() { record.LatencyMs = time.Since(start).Milliseconds() }()
+	reporter = &toolCallRecorder{reporter: reporter, record: record}
+
 	reporter.Send(NewProgressUpdate(
 		schema.Stage_tool_execution_starting,
 		fmt.Sprintf("Running tool %s with arguments: %v", selection.Function.Name, selection.Function.Arguments)))
 
 	tool := findMCPToolByName(a.config.Tools, selection.Function.Name)
+	if tool == nil {
+		available := "none"
+		if len(a.config.Tools) > 0 {
+			available = strings.Join(toolNames(a.config.Tools), ", ")
+		}
+		return a.reportToolFailure(reporter, selection.Function.Name, fmt.Sprintf(
+			"Unknown tool %q. Available tools: %s. Select one of the available tools.",
+			selection.Function.Name, available))
+	}
+
+	if tool.Handler == nil {
+		return a.reportToolFailure(reporter, selection.Function.Name, fmt.Sprintf(
+			"Tool %q is not available right now (no handler configured).", selection.Function.Name))
+	}
+
+	// Validate arguments against the tool schema so the model can retry with fixed arguments
+	args, err := validateToolArguments(tool.inputSchema(), selection.Function.Arguments)
+	if err != nil {
+		return a.reportToolFailure(reporter, selection.Function.Name, fmt.Sprintf(
+			"Invalid arguments for tool %q: %v. Fix the arguments and call the tool again.", selection.Function.Name, err))
+	}
 
 	// Format tool inputs for summarization context
-	toolInputsMD := formatToolInputsToMarkdown(selection.Function.Name, selection.Function.Arguments)
+	toolInputsMD := formatToolInputsToMarkdown(selection.Function.Name, args)
 
 	// Execute the tool handler
-	toolResultChan := tool.Handler(ctx, selection.Function.Arguments)
+	toolResultChan, err := invokeHandler(ctx, tool, args)
+	if err != nil {
+		logger.Error("Tool handler failed", zap.String("tool", selection.Function.Name), zap.Error(err))
+		return a.reportToolFailure(reporter, selection.Function.Name, fmt.Sprintf(
+			"Tool %q failed: %v. Check the arguments and try again.", selection.Function.Name, err))
+	}
 
 	r := &ToolResultRenderer{
 		reporter:           reporter,
 		summarizationModel: a.config.MiniModel,
 		toolName:           selection.Function.Name,
+		onUsage:            a.usage.recorder(UsageRoleMiniModel, a.config.MiniModel),
+		prompts:            a.config.Prompts,
+		promptVars:         a.promptVars(nil),
 	}
 
 	toolResultChunks, err := r.Render(ctx, query, toolInputsMD, toolResultChan, tool.SummarizeContext)
@@ -44,6 +88,118 @@ func (a *Agent) RunTool(ctx context.Context, reporter ProgressReporter, query st
 	return strings.Join(toolResultChunks, "\n\n"), nil
 }
 
+// reportToolFailure reports a tool that could not run and returns the error as a tool result,
+// so the selector sees what went wrong in the next turn and can correct the call.
+func (a *Agent) reportToolFailure(reporter ProgressReporter, toolName, message string) (string, error) {
+	reporter.Send(NewProgressUpdate(schema.Stage_tool_execution_failed, message))
+
+	result := NewToolResultChunk().Error(message).Build()
+	reporter.Send(NewToolExecutionResult(toolName, result))
+
+	return formatToolResultToMD(result), nil
+}
+
+// invokeHandler calls the tool handler, converting a panic or a nil result channel into an error.
+func invokeHandler(ctx context.Context, tool *MCPTool, args api.ToolCallFunctionArguments) (ch <-chan *schema.ToolResultChunk, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			ch, err = nil, fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+
+	ch = tool.Handler(ctx, args)
+	if ch == nil {
+		return nil, fmt.Errorf("handler returned no results")
+	}
+	return ch, nil
+}
+
+// StreamToolResults runs produce in a new goroutine and returns the channel it sends results to,
+// closed once produce returns. Use it in handlers that produce results asynchronously: a panic in
+// produce is recovered and sent as an error result instead of crashing the process. The caller
+// stops reading when ctx is done, so produce should select on ctx.Done() when sending, e.g. with
+// SendToolResult.
+func StreamToolResults(ctx context.Context, produce func(ctx context.Context, ch chan<- *schema.ToolResultChunk)) <-chan *schema.ToolResultChunk {
+	ch := make(chan *schema.ToolResultChunk, 1)
+	go func() {
+		defer close(ch)
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("Tool result producer panicked", zap.Any("panic", r), zap.Stack("stack"))
+				SendToolResult(ctx, ch, NewToolResultChunk().Error(fmt.Sprintf("handler panicked: %v", r)).Build())
+			}
+		}()
+		produce(ctx, ch)
+	}()
+	return ch
+}
+
+// SendToolResult sends chunk to ch unless ctx is done first, and reports whether it was sent.
+func SendToolResult(ctx context.Context, ch chan<- *schema.ToolResultChunk, chunk *schema.ToolResultChunk) bool {
+	select {
+	case ch <- chunk:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// RunTools executes the tool calls of a single turn concurrently, running at most
+// AgentConfig.MaxParallelTools at a time. Results are returned in selection order;
+// a call that failed leaves an empty string at its index.
+func (a *Agent) RunTools(ctx context.Context, reporter ProgressReporter, query string, calls []api.ToolCall) []string {
+	results, _ := a.runTools(ctx, reporter, query, calls)
+	return results
+}
+
+// runTools runs the tool calls of a turn like RunTools, also returning a record of each call.
+func (a *Agent) runTools(ctx context.Context, reporter ProgressReporter, query string, calls []api.ToolCall) ([]string, []memory.ToolCallRecord) {
+	results := make([]string, len(calls))
+	records := make([]memory.ToolCallRecord, len(calls))
+	if len(calls) == 0 {
+		return results, records
+	}
+
+	limit := a.config.MaxParallelTools
+	if limit <= 0 || limit > len(calls) {
+		limit = len(calls)
+	}
+
+	// Reporters such as gRPC streams don't allow concurrent Send calls.
+	reporter = &synchronizedReporter{reporter: reporter}
+
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := range calls {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := a.runTool(ctx, reporter, query, &calls[i], &records[i])
+			if err == nil {
+				results[i] = result
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return results, records
+}
+
+// synchronizedReporter serializes Send calls on the wrapped reporter.
+type synchronizedReporter struct {
+	mu       sync.Mutex
+	reporter ProgressReporter
+}
+
+func (r *synchronizedReporter) Send(event *schema.AgentStreamChunk) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reporter.Send(event)
+}
+
 // formatToolInputsToMarkdown formats tool inputs as markdown for use in summarization prompts
 func formatToolInputsToMarkdown(toolName string, params api.ToolCallFunctionArguments) string {
 	if len(params) == 0 {
@@ -1,12 +1,18 @@
 package agentboot
 
 import (
+	"context"
+	"fmt"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/SaiNageswarS/agent-boot/prompts"
+	"github.com/SaiNageswarS/agent-boot/schema"
 	"github.com/ollama/ollama/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFormatToolInputsToMarkdown(t *testing.T) {
@@ -146,3 +152,229 @@ func TestEndToEndToolInputsInSummarization(t *testing.T) {
 	// Verify instructions mention both question and tool inputs
 	assert.Contains(t, userPrompt, "user's question and tool inputs", "Instructions should mention both")
 }
+
+func TestRunToolsParallel(t *testing.T) {
+	var running, peak atomic.Int32
+	slowTool := MCPTool{
+		Tool: api.Tool{Function: api.ToolFunction{Name: "slow"}},
+		Handler: func(ctx context.Context, params api.ToolCallFunctionArguments) <-chan *schema.ToolResultChunk {
+			ch := make(chan *schema.ToolResultChunk, 1)
+			go func() {
+				defer close(ch)
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				// Later calls finish first to check results keep selection order
+				time.Sleep(time.Duration(30-10*params["i"].(int)) * time.Millisecond)
+				running.Add(-1)
+				ch <- &schema.ToolResultChunk{Sentences: []string{fmt.Sprintf("result %d", params["i"])}}
+			}()
+			return ch
+		},
+	}
+
+	calls := make([]api.ToolCall, 3)
+	for i := range calls {
+		calls[i] = api.ToolCall{Function: api.ToolCallFunction{Name: "slow", Arguments: map[string]any{"i": i}}}
+	}
+
+	t.Run("all at once", func(t *testing.T) {
+		running.Store(0)
+		peak.Store(0)
+		agent := NewAgentBuilder().
+			WithToolSelector(&mockLLMClient{}).
+			WithMaxParallelTools(0).
+			AddTool(slowTool).
+			Build()
+
+		results := agent.RunTools(context.Background(), &MockProgressReporter{}, "query", calls)
+
+		assert.Len(t, results, 3)
+		for i, result := range results {
+			assert.Contains(t, result, fmt.Sprintf("result %d", i))
+		}
+		assert.Equal(t, int32(3), peak.Load())
+	})
+
+	t.Run("bounded concurrency", func(t *testing.T) {
+		running.Store(0)
+		peak.Store(0)
+		agent := NewAgentBuilder().
+			WithToolSelector(&mockLLMClient{}).
+			WithMaxParallelTools(1).
+			AddTool(slowTool).
+			Build()
+
+		reporter := &MockProgressReporter{}
+		results := agent.RunTools(context.Background(), reporter, "query", calls)
+
+		assert.Len(t, results, 3)
+		for i, result := range results {
+			assert.Contains(t, result, fmt.Sprintf("result %d", i))
+		}
+		assert.Equal(t, int32(1), peak.Load())
+
+		toolResults := 0
+		for _, event := range reporter.GetEvents() {
+			if chunk := event.GetToolResultChunk(); chunk != nil {
+				assert.Equal(t, "slow", chunk.ToolName)
+				toolResults++
+			}
+		}
+		assert.Equal(t, 3, toolResults)
+	})
+}
+
+func TestRunToolsEmpty(t *testing.T) {
+	agent := NewAgentBuilder().WithToolSelector(&mockLLMClient{}).Build()
+
+	results := agent.RunTools(context.Background(), &MockProgressReporter{}, "query", nil)
+
+	assert.Empty(t, results)
+}
+
+func TestRunToolFailures(t *testing.T) {
+	calculator := MCPTool{
+		Tool: api.Tool{Function: api.ToolFunction{Name: "calculator"}},
+		Handler: func(ctx context.Context, params api.ToolCallFunctionArguments) <-chan *schema.ToolResultChunk {
+			ch := make(chan *schema.ToolResultChunk, 1)
+			close(ch)
+			return ch
+		},
+	}
+	noHandler := MCPTool{Tool: api.Tool{Function: api.ToolFunction{Name: "no-handler"}}}
+	panicking := MCPTool{
+		Tool: api.Tool{Function: api.ToolFunction{Name: "panicking"}},
+		Handler: func(ctx context.Context, params api.ToolCallFunctionArguments) <-chan *schema.ToolResultChunk {
+			panic("boom")
+		},
+	}
+	nilChannel := MCPTool{
+		Tool: api.Tool{Function: api.ToolFunction{Name: "nil-channel"}},
+		Handler: func(ctx context.Context, params api.ToolCallFunctionArguments) <-chan *schema.ToolResultChunk {
+			return nil
+		},
+	}
+
+	agent := NewAgentBuilder().
+		WithToolSelector(&mockLLMClient{}).
+		AddTool(calculator).
+		AddTool(noHandler).
+		AddTool(panicking).
+		AddTool(nilChannel).
+		Build()
+
+	tests := []struct {
+		toolName string
+		expected []string
+	}{
+		{"weather", []string{"Unknown tool", "Available tools: calculator, no-handler, panicking, nil-channel"}},
+		{"no-handler", []string{"no handler configured"}},
+		{"panicking", []string{"handler panicked: boom"}},
+		{"nil-channel", []string{"handler returned no results"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.toolName, func(t *testing.T) {
+			reporter := &MockProgressReporter{}
+			selection := &api.ToolCall{Function: api.ToolCallFunction{Name: tt.toolName}}
+
+			var result string
+			var err error
+			assert.NotPanics(t, func() {
+				result, err = agent.RunTool(context.Background(), reporter, "query", selection)
+			})
+
+			assert.NoError(t, err)
+			assert.Contains(t, result, "**Error:**")
+			for _, expected := range tt.expected {
+				assert.Contains(t, result, expected)
+			}
+
+			hasFailedStage := false
+			hasErrorChunk := false
+			for _, event := range reporter.GetEvents() {
+				if p := event.GetProgressUpdateChunk(); p != nil && p.Stage == schema.Stage_tool_execution_failed {
+					hasFailedStage = true
+				}
+				if chunk := event.GetToolResultChunk(); chunk != nil && chunk.Error != "" {
+					assert.Equal(t, tt.toolName, chunk.ToolName)
+					hasErrorChunk = true
+				}
+			}
+			assert.True(t, hasFailedStage, "Should report tool_execution_failed stage")
+			assert.True(t, hasErrorChunk, "Should report a tool result with the error")
+		})
+	}
+}
+
+func TestAgentExecuteFeedsUnknownToolErrorBack(t *testing.T) {
+	selector := &testLLMClient{
+		model: "selector",
+		toolCallsPerTurn: [][]api.ToolCall{
+			{{Function: api.ToolCallFunction{Name: "hallucinated"}}},
+			{},
+		},
+	}
+
+	agent := NewAgentBuilder().
+		WithBigModel(&mockLLMClient{}).
+		WithToolSelector(selector).
+		AddTool(MCPTool{Tool: api.Tool{Function: api.ToolFunction{Name: "search"}}}).
+		Build()
+
+	result, err := agent.Execute(context.Background(), &MockProgressReporter{}, &schema.GenerateAnswerRequest{Question: "q"})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, 2, selector.callCount)
+
+	// The second selection sees the error from the hallucinated call
+	secondTurn := selector.messagesPerCall[1]
+	assert.Contains(t, secondTurn[len(secondTurn)-1].Content, `Unknown tool "hallucinated"`)
+}
+
+func TestStreamToolResultsRecoversPanic(t *testing.T) {
+	ch := StreamToolResults(context.Background(), func(ctx context.Context, ch chan<- *schema.ToolResultChunk) {
+		SendToolResult(ctx, ch, NewToolResultChunk().Sentences("partial").Build())
+		panic("late boom")
+	})
+
+	var chunks []*schema.ToolResultChunk
+	for chunk := range ch {
+		chunks = append(chunks, chunk)
+	}
+
+	require.Len(t, chunks, 2)
+	assert.Equal(t, []string{"partial"}, chunks[0].Sentences)
+	assert.Equal(t, "handler panicked: late boom", chunks[1].Error)
+}
+
+func TestStreamToolResultsStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sent := make(chan bool)
+	ch := StreamToolResults(ctx, func(ctx context.Context, ch chan<- *schema.ToolResultChunk) {
+		// The buffer takes the first chunk; nobody reads the second
+		SendToolResult(ctx, ch, NewToolResultChunk().Sentences("first").Build())
+		sent <- true
+		sent <- SendToolResult(ctx, ch, NewToolResultChunk().Sentences("second").Build())
+	})
+
+	<-sent
+	cancel()
+	select {
+	case ok := <-sent:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Sending blocked after cancellation")
+	}
+
+	chunk := <-ch
+	assert.Equal(t, []string{"first"}, chunk.Sentences)
+	_, open := <-ch
+	assert.False(t, open)
+}
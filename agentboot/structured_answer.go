@@ -0,0 +1,157 @@
+package agentboot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/SaiNageswarS/agent-boot/schema"
+	"github.com/SaiNageswarS/go-api-boot/logger"
+	"go.uber.org/zap"
+)
+
+// Metadata keys of StreamComplete.Metadata describing a structured answer.
+const (
+	MetadataAnswerJSON   = "answer_json"   // the answer as normalized JSON, only set when it conforms to the schema
+	MetadataAnswerErrors = "answer_errors" // problems of an answer still not conforming after all repair attempts
+)
+
+// SchemaOf returns the JSON schema of T, derived from its fields and struct tags like the
+// parameters of NewTypedTool. Use it with WithAnswerSchema and decode the answer with ParseAnswer.
+func SchemaOf[T any]() map[string]any {
+	return typeSchema(reflect.TypeFor[T](), map[reflect.Type]bool{})
+}
+
+// ParseAnswer decodes the structured answer of a request executed with an answer schema into T.
+func ParseAnswer[T any](complete *schema.StreamComplete) (T, error) {
+	var answer T
+	data, ok := complete.GetMetadata()[MetadataAnswerJSON]
+	if !ok {
+		if problems := complete.GetMetadata()[MetadataAnswerErrors]; problems != "" {
+			return answer, fmt.Errorf("answer does not conform to the schema: %s", problems)
+		}
+		return answer, errors.New("no structured answer in response")
+	}
+
+	if err := json.Unmarshal([]byte(data), &answer); err != nil {
+		return answer, fmt.Errorf("error decoding structured answer: %w", err)
+	}
+	return answer, nil
+}
+
+// generateStructuredAnswer asks the big model for an answer conforming to AnswerSchema, using the
+// provider's JSON mode where available. Answers that don't conform are sent back to the model with
+// the problems found, up to AnswerRepairAttempts times. The answer is buffered and sent as a single
+// chunk once validated, and the normalized JSON is stored in response.Metadata.
+func (a *Agent) generateStructuredAnswer(ctx context.Context, reporter ProgressReporter, msgs []llm.Message, response *schema.StreamComplete) (string, error) {
+	answerSchema := a.config.AnswerSchema
+	schemaJSON, err := json.MarshalIndent(answerSchema, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error encoding answer schema: %w", err)
+	}
+
+	instructions, err := a.config.Prompts.RenderStructuredAnswerPrompt(string(schemaJSON), a.promptVars(msgs))
+	if err != nil {
+		return "", err
+	}
+	systemPrompt := strings.TrimSpace(a.config.SystemPrompt + "\n\n" + instructions)
+
+	// Repair turns are only added to this request, not to the saved conversation
+	messages := append([]llm.Message(nil), msgs...)
+
+	var answer string
+	for attempt := 0; ; attempt++ {
+		var inference strings.Builder
+		err := a.config.BigModel.GenerateInference(
+			ctx, a.contextMessages(systemPrompt, messages),
+			func(chunk string) error {
+				inference.WriteString(chunk)
+				return nil
+			},
+			llm.WithMaxTokens(a.config.MaxTokens),
+			llm.WithTemperature(a.config.Temperature),
+			llm.WithSystemPrompt(systemPrompt),
+			llm.WithStreaming(true),
+			llm.WithJSONSchema(answerSchema),
+			llm.WithUsageCallback(a.usage.recorder(UsageRoleBigModel, a.config.BigModel)),
+		)
+		if err != nil {
+			return answer, err
+		}
+		answer = inference.String()
+
+		value, problems := parseStructuredAnswer(answerSchema, answer)
+		if len(problems) == 0 {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return answer, fmt.Errorf("error encoding structured answer: %w", err)
+			}
+			response.Metadata[MetadataAnswerJSON] = string(data)
+			break
+		}
+
+		if attempt >= a.config.AnswerRepairAttempts {
+			response.Metadata[MetadataAnswerErrors] = strings.Join(problems, "; ")
+			reporter.Send(NewStreamError(
+				fmt.Sprintf("answer does not conform to the schema: %s", strings.Join(problems, "; ")),
+				"invalid_structured_answer"))
+			break
+		}
+
+		logger.Info("Structured answer does not conform to the schema, asking for a repair",
+			zap.Int("attempt", attempt+1),
+			zap.Strings("problems", problems))
+
+		repairPrompt, err := a.config.Prompts.RenderAnswerRepairPrompt(problems, a.promptVars(msgs))
+		if err != nil {
+			return answer, err
+		}
+		messages = append(messages,
+			llm.Message{Role: "assistant", Content: answer},
+			llm.Message{Role: "user", Content: repairPrompt})
+	}
+
+	reporter.Send(NewAnswerChunk(&schema.AnswerChunk{Content: answer}))
+	return answer, nil
+}
+
+// parseStructuredAnswer decodes the JSON in answer and validates it against answerSchema, returning
+// the coerced value. Markdown code fences and text around the JSON are ignored.
+func parseStructuredAnswer(answerSchema map[string]any, answer string) (any, []string) {
+	var value any
+	if err := json.Unmarshal([]byte(extractJSON(answer)), &value); err != nil {
+		return nil, []string{fmt.Sprintf("the answer is not valid JSON: %v", err)}
+	}
+	return coerceValue("", answerSchema, value)
+}
+
+// extractJSON returns the JSON value in text, dropping code fences and text before or after it.
+func extractJSON(text string) string {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text, "```")
+		text = strings.TrimPrefix(text, "json")
+		text = strings.TrimSuffix(strings.TrimSpace(text), "```")
+		text = strings.TrimSpace(text)
+	}
+	if json.Valid([]byte(text)) {
+		return text
+	}
+
+	start := strings.IndexAny(text, "{[")
+	if start < 0 {
+		return text
+	}
+	closing := "}"
+	if text[start] == '[' {
+		closing = "]"
+	}
+	if end := strings.LastIndex(text, closing); end > start {
+		return text[start : end+1]
+	}
+	return text
+}
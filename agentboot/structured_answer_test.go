@@ -0,0 +1,158 @@
+package agentboot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SaiNageswarS/agent-boot/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cityAnswer struct {
+	City       string   `json:"city" required:"true" description:"Name of the city"`
+	Population int      `json:"population" required:"true"`
+	Landmarks  []string `json:"landmarks,omitempty"`
+}
+
+func newStructuredAnswerAgent(bigModel *testLLMClient) *Agent {
+	return NewAgentBuilder().
+		WithBigModel(bigModel).
+		WithToolSelector(&testLLMClient{model: "selector"}).
+		WithAnswerSchema(SchemaOf[cityAnswer]()).
+		Build()
+}
+
+func TestSchemaOf(t *testing.T) {
+	s := SchemaOf[cityAnswer]()
+
+	assert.Equal(t, "object", s["type"])
+	assert.ElementsMatch(t, []string{"city", "population"}, s["required"])
+
+	props := s["properties"].(map[string]any)
+	assert.Equal(t, "string", props["city"].(map[string]any)["type"])
+	assert.Equal(t, "Name of the city", props["city"].(map[string]any)["description"])
+	assert.Equal(t, "integer", props["population"].(map[string]any)["type"])
+	assert.Equal(t, "array", props["landmarks"].(map[string]any)["type"])
+}
+
+func TestExtractJSON(t *testing.T) {
+	tests := []struct {
+		name, text, want string
+	}{
+		{"plain", `{"city":"Paris"}`, `{"city":"Paris"}`},
+		{"code fence", "```json\n{\"city\":\"Paris\"}\n```", `{"city":"Paris"}`},
+		{"surrounding text", "Here is the answer: {\"city\":\"Paris\"} Hope it helps!", `{"city":"Paris"}`},
+		{"array", "Result: [1, 2]", `[1, 2]`},
+		{"no json", "Paris", "Paris"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, extractJSON(tt.text))
+		})
+	}
+}
+
+func TestAgentExecuteStructuredAnswer(t *testing.T) {
+	bigModel := &testLLMClient{model: "big", response: "```json\n{\"city\": \"Paris\", \"population\": \"2100000\"}\n```"}
+	agent := newStructuredAnswerAgent(bigModel)
+	reporter := &MockProgressReporter{}
+
+	result, err := agent.Execute(context.Background(), reporter, &schema.GenerateAnswerRequest{Question: "Capital of France?"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, bigModel.callCount)
+	assert.Equal(t, bigModel.response, result.Answer, "Answer keeps the raw text")
+	assert.JSONEq(t, `{"city":"Paris","population":2100000}`, result.Metadata[MetadataAnswerJSON])
+
+	parsed, err := ParseAnswer[cityAnswer](result)
+	require.NoError(t, err)
+	assert.Equal(t, cityAnswer{City: "Paris", Population: 2100000}, parsed)
+
+	// The validated answer is sent in one chunk
+	var chunks []string
+	for _, event := range reporter.GetEvents() {
+		if answer := event.GetAnswer(); answer != nil {
+			chunks = append(chunks, answer.Content)
+		}
+	}
+	assert.Equal(t, []string{bigModel.response}, chunks)
+}
+
+func TestAgentExecuteStructuredAnswerRepair(t *testing.T) {
+	bigModel := &testLLMClient{model: "big", responses: []string{
+		`{"city": "Paris"}`,
+		`{"city": "Paris", "population": 2100000}`,
+	}}
+	agent := newStructuredAnswerAgent(bigModel)
+
+	result, err := agent.Execute(context.Background(), &MockProgressReporter{}, &schema.GenerateAnswerRequest{Question: "Capital of France?"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, bigModel.callCount)
+	assert.Equal(t, `{"city": "Paris", "population": 2100000}`, result.Answer)
+	assert.JSONEq(t, `{"city":"Paris","population":2100000}`, result.Metadata[MetadataAnswerJSON])
+
+	// The repair request shows the invalid answer and the problems found
+	repair := bigModel.messagesPerCall[1]
+	require.Len(t, repair, 3)
+	assert.Equal(t, "assistant", repair[1].Role)
+	assert.Equal(t, `{"city": "Paris"}`, repair[1].Content)
+	assert.Equal(t, "user", repair[2].Role)
+	assert.Contains(t, repair[2].Content, `missing required argument "population"`)
+}
+
+func TestAgentExecuteStructuredAnswerInvalid(t *testing.T) {
+	bigModel := &testLLMClient{model: "big", response: "The capital of France is Paris."}
+	agent := NewAgentBuilder().
+		WithBigModel(bigModel).
+		WithToolSelector(&testLLMClient{model: "selector"}).
+		WithAnswerSchema(SchemaOf[cityAnswer]()).
+		WithAnswerRepairAttempts(1).
+		Build()
+	reporter := &MockProgressReporter{}
+
+	result, err := agent.Execute(context.Background(), reporter, &schema.GenerateAnswerRequest{Question: "Capital of France?"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, bigModel.callCount)
+	assert.Equal(t, "The capital of France is Paris.", result.Answer)
+	assert.NotContains(t, result.Metadata, MetadataAnswerJSON)
+	assert.Contains(t, result.Metadata[MetadataAnswerErrors], "not valid JSON")
+
+	_, err = ParseAnswer[cityAnswer](result)
+	assert.ErrorContains(t, err, "answer does not conform to the schema")
+
+	hasError := false
+	for _, event := range reporter.GetEvents() {
+		if e := event.GetError(); e != nil && e.ErrorCode == "invalid_structured_answer" {
+			hasError = true
+		}
+	}
+	assert.True(t, hasError, "Should report the invalid answer")
+}
+
+func TestAgentExecuteAnswerSchemaPerRequest(t *testing.T) {
+	bigModel := &testLLMClient{model: "big", response: `{"answer": 4}`}
+	agent := NewAgentBuilder().
+		WithBigModel(bigModel).
+		WithToolSelector(&testLLMClient{model: "selector"}).
+		Build()
+
+	result, err := agent.Execute(context.Background(), &MockProgressReporter{}, &schema.GenerateAnswerRequest{
+		Question: "What is 2+2?",
+		Metadata: map[string]string{
+			MetadataAnswerSchema: `{"type": "object", "properties": {"answer": {"type": "integer"}}, "required": ["answer"]}`,
+		},
+	})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"answer":4}`, result.Metadata[MetadataAnswerJSON])
+	assert.Nil(t, agent.config.AnswerSchema, "The agent's config is not modified")
+}
+
+func TestParseAnswerWithoutSchema(t *testing.T) {
+	_, err := ParseAnswer[cityAnswer](&schema.StreamComplete{Answer: "Paris"})
+	assert.EqualError(t, err, "no structured answer in response")
+}
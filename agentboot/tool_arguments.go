@@ -0,0 +1,347 @@
+package agentboot
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ollama/ollama/api"
+)
+
+// inputSchema returns the JSON schema of the tool's parameters as a generic map.
+func (t *MCPTool) inputSchema() map[string]any {
+	if t.InputSchema != nil {
+		return t.InputSchema
+	}
+
+	data, err := json.Marshal(t.Function.Parameters)
+	if err != nil {
+		return nil
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil
+	}
+	return schema
+}
+
+// validateToolArguments checks args against the tool parameter schema and returns a coerced copy.
+// Obvious mismatches are fixed instead of rejected: numeric and boolean strings become numbers and
+// booleans, numbers become strings, and a single value becomes a one-element array. Numbers,
+// integers included, are returned as float64 like encoding/json decodes them. A null optional
+// argument counts as omitted, and omitted optional arguments with a default get it.
+// Arguments not declared in the schema are passed through.
+// The returned error lists every problem found so the model can fix all of them in one retry.
+func validateToolArguments(schema map[string]any, args api.ToolCallFunctionArguments) (api.ToolCallFunctionArguments, error) {
+	if len(schemaProperties(schema)) == 0 {
+		return args, nil
+	}
+
+	value, problems := coerceValue("", schema, map[string]any(args))
+	if len(problems) > 0 {
+		return args, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return api.ToolCallFunctionArguments(value.(map[string]any)), nil
+}
+
+// coerceValue validates v against schema, returning the coerced value and any problems found.
+// path names the value in error messages and is empty for the top-level arguments object.
+func coerceValue(path string, schema map[string]any, v any) (any, []string) {
+	types := schemaTypes(schema)
+	if len(types) == 0 {
+		return v, nil
+	}
+
+	var coerced any
+	var problems []string
+	ok := false
+	for _, typ := range types {
+		if coerced, problems, ok = coerceToType(path, typ, schema, v); ok {
+			break
+		}
+	}
+	if !ok {
+		if problems == nil {
+			problems = []string{fmt.Sprintf("%s: expected %s, got %s", describePath(path), strings.Join(types, " or "), describeValue(v))}
+		}
+		return v, problems
+	}
+
+	if enum, hasEnum := schema["enum"].([]any); hasEnum && len(enum) > 0 && !enumContains(enum, coerced) {
+		return v, append(problems, fmt.Sprintf("%s: %s is not one of %s", describePath(path), describeValue(coerced), describeEnum(enum)))
+	}
+
+	if f, isNumber := toFloat(coerced); isNumber && !isString(coerced) {
+		if minimum, hasMin := toFloat(schema["minimum"]); hasMin && f < minimum {
+			return v, append(problems, fmt.Sprintf("%s: %v is less than the minimum %v", describePath(path), coerced, minimum))
+		}
+		if maximum, hasMax := toFloat(schema["maximum"]); hasMax && f > maximum {
+			return v, append(problems, fmt.Sprintf("%s: %v is greater than the maximum %v", describePath(path), coerced, maximum))
+		}
+	}
+
+	return coerced, problems
+}
+
+// coerceToType converts v to the JSON schema type typ. ok is false when v cannot represent typ.
+func coerceToType(path, typ string, schema map[string]any, v any) (coerced any, problems []string, ok bool) {
+	switch typ {
+	case "string":
+		switch x := v.(type) {
+		case string:
+			return x, nil, true
+		case float64, int, int64, bool:
+			return fmt.Sprint(x), nil, true
+		}
+
+	case "number":
+		if f, isNumber := toFloat(v); isNumber {
+			return f, nil, true
+		}
+
+	case "integer":
+		if f, isNumber := toFloat(v); isNumber && f == math.Trunc(f) {
+			return f, nil, true
+		}
+
+	case "boolean":
+		switch x := v.(type) {
+		case bool:
+			return x, nil, true
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(x)); err == nil {
+				return b, nil, true
+			}
+		}
+
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		elems := toSlice(v)
+		result := make([]any, len(elems))
+		for i, elem := range elems {
+			var p []string
+			result[i], p = coerceValue(fmt.Sprintf("%s[%d]", path, i), items, elem)
+			problems = append(problems, p...)
+		}
+		return result, problems, true
+
+	case "object":
+		obj, isObject := v.(map[string]any)
+		if !isObject {
+			if args, isArgs := v.(api.ToolCallFunctionArguments); isArgs {
+				obj, isObject = args, true
+			}
+		}
+		if !isObject {
+			return nil, nil, false
+		}
+		return coerceObject(path, schema, obj)
+
+	case "null":
+		if v == nil {
+			return nil, nil, true
+		}
+	}
+
+	return nil, nil, false
+}
+
+// coerceObject validates the properties of obj, reporting missing required properties.
+func coerceObject(path string, schema map[string]any, obj map[string]any) (any, []string, bool) {
+	props := schemaProperties(schema)
+	result := make(map[string]any, len(obj))
+	var problems []string
+
+	required := schemaRequired(schema)
+	for _, name := range required {
+		if _, present := obj[name]; !present {
+			problems = append(problems, fmt.Sprintf("missing required argument %q", joinPath(path, name)))
+		}
+	}
+
+	// Models often send null for optional arguments they leave out, so treat those as omitted.
+	// The caller's map is left untouched.
+	obj = maps.Clone(obj)
+	maps.DeleteFunc(obj, func(name string, value any) bool {
+		propSchema, declared := props[name].(map[string]any)
+		return value == nil && declared && !slices.Contains(required, name) && !slices.Contains(schemaTypes(propSchema), "null")
+	})
+
+	// Fill omitted arguments that declare a default
+	for name, prop := range props {
+		propSchema, _ := prop.(map[string]any)
+		if def, hasDefault := propSchema["default"]; hasDefault {
+			if _, present := obj[name]; !present {
+				obj[name] = def
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		propSchema, declared := props[k].(map[string]any)
+		if !declared {
+			result[k] = obj[k]
+			continue
+		}
+
+		var p []string
+		result[k], p = coerceValue(joinPath(path, k), propSchema, obj[k])
+		problems = append(problems, p...)
+	}
+
+	return result, problems, true
+}
+
+func isString(v any) bool {
+	_, ok := v.(string)
+	return ok
+}
+
+func schemaProperties(schema map[string]any) map[string]any {
+	props, _ := schema["properties"].(map[string]any)
+	return props
+}
+
+func schemaRequired(schema map[string]any) []string {
+	var required []string
+	switch r := schema["required"].(type) {
+	case []string:
+		required = r
+	case []any:
+		for _, name := range r {
+			if s, ok := name.(string); ok {
+				required = append(required, s)
+			}
+		}
+	}
+	return required
+}
+
+// schemaTypes returns the allowed types of a schema; "type" may be a string or a list of strings.
+func schemaTypes(schema map[string]any) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		if t != "" {
+			return []string{t}
+		}
+	case []string:
+		return t
+	case []any:
+		types := make([]string, 0, len(t))
+		for _, typ := range t {
+			if s, ok := typ.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// toFloat converts JSON numbers, Go numeric types and numeric strings to float64.
+func toFloat(v any) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case float32:
+		return float64(x), true
+	case int:
+		return float64(x), true
+	case int32:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case json.Number:
+		f, err := x.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// toSlice returns v as a slice, wrapping a single non-slice value in a one-element slice.
+func toSlice(v any) []any {
+	if v == nil {
+		return []any{}
+	}
+	if s, ok := v.([]any); ok {
+		return s
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []any{v}
+	}
+
+	s := make([]any, rv.Len())
+	for i := range s {
+		s[i] = rv.Index(i).Interface()
+	}
+	return s
+}
+
+func enumContains(enum []any, v any) bool {
+	return slices.ContainsFunc(enum, func(allowed any) bool {
+		if af, ok := toFloat(allowed); ok {
+			if vf, ok := toFloat(v); ok {
+				return af == vf
+			}
+		}
+		return fmt.Sprint(allowed) == fmt.Sprint(v)
+	})
+}
+
+func describeEnum(enum []any) string {
+	values := make([]string, len(enum))
+	for i, v := range enum {
+		values[i] = fmt.Sprintf("%q", fmt.Sprint(v))
+	}
+	return "[" + strings.Join(values, ", ") + "]"
+}
+
+func describeValue(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("string %q", x)
+	case bool:
+		return fmt.Sprintf("boolean %t", x)
+	case float64, float32, int, int32, int64:
+		return fmt.Sprintf("number %v", x)
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func describePath(path string) string {
+	if path == "" {
+		return "arguments"
+	}
+	return fmt.Sprintf("argument %q", path)
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,248 @@
+package agentboot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SaiNageswarS/agent-boot/schema"
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testArgumentSchema() map[string]any {
+	return map[string]any{
+		"type":     "object",
+		"required": []any{"query", "limit"},
+		"properties": map[string]any{
+			"query":   map[string]any{"type": "string"},
+			"limit":   map[string]any{"type": "integer"},
+			"score":   map[string]any{"type": "number"},
+			"exact":   map[string]any{"type": "boolean"},
+			"sort":    map[string]any{"type": "string", "enum": []any{"asc", "desc"}},
+			"tags":    map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"weights": map[string]any{"type": "array", "items": map[string]any{"type": "number"}},
+		},
+	}
+}
+
+func TestValidateToolArgumentsValid(t *testing.T) {
+	args := api.ToolCallFunctionArguments{
+		"query":   "golang",
+		"limit":   float64(10),
+		"score":   0.5,
+		"exact":   true,
+		"sort":    "asc",
+		"tags":    []any{"a", "b"},
+		"weights": []any{1.0, 2.5},
+		"extra":   "passed through",
+	}
+
+	result, err := validateToolArguments(testArgumentSchema(), args)
+
+	require.NoError(t, err)
+	assert.Equal(t, "golang", result["query"])
+	assert.Equal(t, 10.0, result["limit"])
+	assert.Equal(t, 0.5, result["score"])
+	assert.Equal(t, true, result["exact"])
+	assert.Equal(t, []any{"a", "b"}, result["tags"])
+	assert.Equal(t, "passed through", result["extra"])
+}
+
+func TestValidateToolArgumentsCoercion(t *testing.T) {
+	args := api.ToolCallFunctionArguments{
+		"query":   42.0,
+		"limit":   "5",
+		"score":   " 0.75 ",
+		"exact":   "false",
+		"tags":    "single",
+		"weights": []string{"1", "2.5"},
+	}
+
+	result, err := validateToolArguments(testArgumentSchema(), args)
+
+	require.NoError(t, err)
+	assert.Equal(t, "42", result["query"])
+	assert.Equal(t, 5.0, result["limit"])
+	assert.Equal(t, 0.75, result["score"])
+	assert.Equal(t, false, result["exact"])
+	assert.Equal(t, []any{"single"}, result["tags"])
+	assert.Equal(t, []any{1.0, 2.5}, result["weights"])
+
+	// The input is not modified
+	assert.Equal(t, "5", args["limit"])
+}
+
+func TestValidateToolArgumentsNullOptional(t *testing.T) {
+	schema := testArgumentSchema()
+	schema["properties"].(map[string]any)["sort"].(map[string]any)["default"] = "asc"
+
+	result, err := validateToolArguments(schema, api.ToolCallFunctionArguments{
+		"query": "golang",
+		"limit": 5,
+		"score": nil,
+		"sort":  nil,
+		"extra": nil,
+	})
+
+	require.NoError(t, err)
+	assert.NotContains(t, result, "score") // treated as omitted
+	assert.Equal(t, "asc", result["sort"]) // omitted, so the default applies
+	assert.Contains(t, result, "extra")    // undeclared arguments pass through
+
+	// Required arguments must not be null
+	_, err = validateToolArguments(schema, api.ToolCallFunctionArguments{"query": "golang", "limit": nil})
+	assert.ErrorContains(t, err, `argument "limit": expected integer, got null`)
+}
+
+func TestValidateToolArgumentsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     api.ToolCallFunctionArguments
+		expected []string
+	}{
+		{
+			name:     "missing required",
+			args:     api.ToolCallFunctionArguments{"query": "golang"},
+			expected: []string{`missing required argument "limit"`},
+		},
+		{
+			name:     "wrong type",
+			args:     api.ToolCallFunctionArguments{"query": "golang", "limit": "ten"},
+			expected: []string{`argument "limit": expected integer, got string "ten"`},
+		},
+		{
+			name:     "non-integral integer",
+			args:     api.ToolCallFunctionArguments{"query": "golang", "limit": 2.5},
+			expected: []string{`argument "limit": expected integer, got number 2.5`},
+		},
+		{
+			name:     "enum violation",
+			args:     api.ToolCallFunctionArguments{"query": "golang", "limit": 1, "sort": "random"},
+			expected: []string{`argument "sort": string "random" is not one of ["asc", "desc"]`},
+		},
+		{
+			name:     "array item type",
+			args:     api.ToolCallFunctionArguments{"query": "golang", "limit": 1, "weights": []any{1.0, "heavy"}},
+			expected: []string{`argument "weights[1]": expected number, got string "heavy"`},
+		},
+		{
+			name: "all problems reported",
+			args: api.ToolCallFunctionArguments{"exact": "maybe"},
+			expected: []string{
+				`missing required argument "query"`,
+				`missing required argument "limit"`,
+				`argument "exact": expected boolean, got string "maybe"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateToolArguments(testArgumentSchema(), tt.args)
+
+			require.Error(t, err)
+			for _, expected := range tt.expected {
+				assert.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
+
+func TestValidateToolArgumentsNestedObject(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"filters": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type":     "object",
+					"required": []any{"field"},
+					"properties": map[string]any{
+						"field": map[string]any{"type": "string"},
+						"value": map[string]any{"type": "number"},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := validateToolArguments(schema, api.ToolCallFunctionArguments{
+		"filters": []any{map[string]any{"field": "age", "value": "30"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []any{map[string]any{"field": "age", "value": 30.0}}, result["filters"])
+
+	_, err = validateToolArguments(schema, api.ToolCallFunctionArguments{
+		"filters": []any{map[string]any{"value": 30.0}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `missing required argument "filters[0].field"`)
+}
+
+func TestValidateToolArgumentsNoSchema(t *testing.T) {
+	args := api.ToolCallFunctionArguments{"anything": "goes"}
+
+	result, err := validateToolArguments(nil, args)
+
+	assert.NoError(t, err)
+	assert.Equal(t, args, result)
+}
+
+func TestMCPToolInputSchema(t *testing.T) {
+	tool := NewMCPToolBuilder("search", "Search").
+		StringParam("query", "Query", true).
+		StringSliceParam("tags", "Tags", false).
+		Build()
+
+	schema := tool.inputSchema()
+
+	assert.Equal(t, "object", schema["type"])
+	assert.Equal(t, []string{"query"}, schemaRequired(schema))
+	props := schemaProperties(schema)
+	assert.Equal(t, "string", props["query"].(map[string]any)["type"])
+	assert.Equal(t, "array", props["tags"].(map[string]any)["type"])
+}
+
+func TestRunToolInvalidArguments(t *testing.T) {
+	handlerCalled := false
+	tool := NewMCPToolBuilder("search", "Search").
+		StringParam("query", "Query", true).
+		StringSliceParam("tags", "Tags", false).
+		WithHandler(func(ctx context.Context, params api.ToolCallFunctionArguments) <-chan *schema.ToolResultChunk {
+			handlerCalled = true
+			assert.Equal(t, []any{"go"}, params["tags"])
+			ch := make(chan *schema.ToolResultChunk, 1)
+			ch <- NewToolResultChunk().Sentences("found").Build()
+			close(ch)
+			return ch
+		}).
+		Build()
+
+	agent := NewAgentBuilder().
+		WithToolSelector(&mockLLMClient{}).
+		AddTool(tool).
+		Build()
+
+	t.Run("invalid arguments are reported back", func(t *testing.T) {
+		reporter := &MockProgressReporter{}
+		result, err := agent.RunTool(context.Background(), reporter, "q", &api.ToolCall{
+			Function: api.ToolCallFunction{Name: "search", Arguments: api.ToolCallFunctionArguments{"tags": "go"}},
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, handlerCalled)
+		assert.Contains(t, result, `Invalid arguments for tool "search"`)
+		assert.Contains(t, result, `missing required argument "query"`)
+	})
+
+	t.Run("coerced arguments reach the handler", func(t *testing.T) {
+		result, err := agent.RunTool(context.Background(), &MockProgressReporter{}, "q", &api.ToolCall{
+			Function: api.ToolCallFunction{Name: "search", Arguments: api.ToolCallFunctionArguments{"query": "x", "tags": "go"}},
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, handlerCalled)
+		assert.Contains(t, result, "found")
+	})
+}
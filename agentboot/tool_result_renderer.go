@@ -19,6 +19,9 @@ type ToolResultRenderer struct {
 	reporter           ProgressReporter
 	summarizationModel llm.LLMClient
 	toolName           string
+	onUsage            func(llm.Usage)
+	prompts            *prompts.Registry
+	promptVars         prompts.Vars
 }
 
 // ToolResultRendererOption is a functional option for configuring ToolResultRenderer
@@ -45,6 +48,22 @@ func WithReporter(reporter ProgressReporter, toolName string) ToolResultRenderer
 	}
 }
 
+// WithUsageCallback receives the token usage of each summarization call.
+func WithUsageCallback(fn func(llm.Usage)) ToolResultRendererOption {
+	return func(r *ToolResultRenderer) {
+		r.onUsage = fn
+	}
+}
+
+// WithPromptRegistry renders the summarization prompts with registry and vars instead of the
+// embedded templates.
+func WithPromptRegistry(registry *prompts.Registry, vars prompts.Vars) ToolResultRendererOption {
+	return func(r *ToolResultRenderer) {
+		r.prompts = registry
+		r.promptVars = vars
+	}
+}
+
 // WithSummarizationModel sets the LLM client for summarizing tool results.
 // This is required when calling Render with summarizeResult=true.
 func WithSummarizationModel(model llm.LLMClient) ToolResultRendererOption {
@@ -126,7 +145,7 @@ func (r *ToolResultRenderer) summarizeResult(ctx context.Context, chunk *schema.
 	// Join all sentences into a single text
 	combinedText := strings.Join(chunk.Sentences, " ")
 
-	systemPrompt, userPrompt, err := prompts.RenderSummarizationPrompt(userQuery, combinedText, toolInputs)
+	systemPrompt, userPrompt, err := r.prompts.RenderSummarizationPrompt(userQuery, combinedText, toolInputs, r.promptVars)
 	if err != nil {
 		// If template rendering fails, keep the original result
 		logger.Error("Failed to render summarization prompt", zap.String("title", chunk.Title), zap.Error(err))
@@ -147,6 +166,7 @@ func (r *ToolResultRenderer) summarizeResult(ctx context.Context, chunk *schema.
 		},
 		llm.WithTemperature(0.3),
 		llm.WithSystemPrompt(systemPrompt),
+		llm.WithUsageCallback(r.onUsage),
 	)
 
 	if err != nil {
@@ -158,7 +178,7 @@ func (r *ToolResultRenderer) summarizeResult(ctx context.Context, chunk *schema.
 	summary := strings.TrimSpace(responseContent.String())
 
 	// Drop irrelevant content
-	if strings.Contains(summary, "# IRRELEVANT") {
+	if strings.Contains(summary, prompts.IrrelevantMarker) {
 		logger.Info("Dropping irrelevant tool result", zap.String("title", chunk.Title))
 		return nil
 	}
@@ -0,0 +1,49 @@
+package agentboot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/SaiNageswarS/agent-boot/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// toolResultChunks returns a closed channel with n chunks of a search tool.
+func toolResultChunks(n int) <-chan *schema.ToolResultChunk {
+	ch := make(chan *schema.ToolResultChunk, n)
+	for i := range n {
+		ch <- NewToolResultChunk().
+			Title(fmt.Sprintf("Result %d", i)).
+			Sentences(strings.Repeat("Paris is pleasant in spring, when parks bloom and crowds are smaller. ", 10)).
+			Build()
+	}
+	close(ch)
+	return ch
+}
+
+func TestToolResultRendererSummarizesChunks(t *testing.T) {
+	r := NewToolResultRenderer(WithSummarizationModel(&mockLLMClient{model: "mini"}))
+
+	results, err := r.Render(context.Background(), "When should I visit Paris?", "", toolResultChunks(8), true)
+	require.NoError(t, err)
+	require.Len(t, results, 8)
+	for _, result := range results {
+		assert.Contains(t, result, "test response")
+		assert.Contains(t, result, "| summarized | true |")
+	}
+}
+
+func BenchmarkToolResultRendererSummarize(b *testing.B) {
+	r := NewToolResultRenderer(WithSummarizationModel(&mockLLMClient{model: "mini"}))
+	toolInputs := formatToolInputsToMarkdown("search", map[string]any{"query": "best time to visit Paris"})
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := r.Render(context.Background(), "When should I visit Paris?", toolInputs, toolResultChunks(32), true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
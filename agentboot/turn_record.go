@@ -0,0 +1,43 @@
+package agentboot
+
+import (
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/SaiNageswarS/agent-boot/memory"
+	"github.com/SaiNageswarS/agent-boot/schema"
+)
+
+// toolCallRecorder forwards the events of one tool call to reporter and records its results and
+// failures in record.
+type toolCallRecorder struct {
+	reporter ProgressReporter
+	mu       sync.Mutex
+	record   *memory.ToolCallRecord
+}
+
+func (r *toolCallRecorder) Send(event *schema.AgentStreamChunk) error {
+	r.mu.Lock()
+	switch chunk := event.ChunkType.(type) {
+	case *schema.AgentStreamChunk_ToolResultChunk:
+		result := chunk.ToolResultChunk
+		r.record.Results = append(r.record.Results, memory.ToolResultRecord{
+			Title:       result.Title,
+			Attribution: result.Attribution,
+			Sentences:   slices.Clone(result.Sentences),
+			Metadata:    maps.Clone(result.Metadata),
+			Error:       result.Error,
+		})
+		if r.record.Error == "" {
+			r.record.Error = result.Error
+		}
+	case *schema.AgentStreamChunk_Error:
+		if r.record.Error == "" {
+			r.record.Error = chunk.Error.ErrorMessage
+		}
+	}
+	r.mu.Unlock()
+
+	return r.reporter.Send(event)
+}
@@ -0,0 +1,99 @@
+package agentboot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/SaiNageswarS/agent-boot/memory"
+	"github.com/SaiNageswarS/agent-boot/schema"
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteRecordsTurn(t *testing.T) {
+	searchTool := MCPTool{
+		Tool: api.Tool{Function: api.ToolFunction{Name: "search"}},
+		Handler: func(ctx context.Context, params api.ToolCallFunctionArguments) <-chan *schema.ToolResultChunk {
+			ch := make(chan *schema.ToolResultChunk, 1)
+			ch <- &schema.ToolResultChunk{
+				Sentences:   []string{"Go was released in 2009."},
+				Title:       "Go (programming language)",
+				Attribution: "https://en.wikipedia.org/wiki/Go_(programming_language)",
+			}
+			close(ch)
+			return ch
+		},
+	}
+
+	selector := &testLLMClient{
+		model: "selector",
+		toolCallsPerTurn: [][]api.ToolCall{{
+			{Function: api.ToolCallFunction{Name: "search", Arguments: api.ToolCallFunctionArguments{"query": "go release"}}},
+			{Function: api.ToolCallFunction{Name: "missing"}},
+		}},
+		usagePerCall: llm.Usage{PromptTokens: 100, CompletionTokens: 10},
+	}
+	bigModel := &testLLMClient{model: "big", response: "Go was released in 2009.", usagePerCall: llm.Usage{PromptTokens: 300, CompletionTokens: 20}}
+	store := memory.NewInMemoryStore(0)
+	agent := NewAgentBuilder().
+		WithBigModel(bigModel).
+		WithToolSelector(selector).
+		WithConversationStore(store, 10).
+		AddTool(searchTool).
+		Build()
+
+	_, err := agent.Execute(context.Background(), &MockProgressReporter{}, &schema.GenerateAnswerRequest{Question: "When was Go released?", SessionId: "s1"})
+	require.NoError(t, err)
+
+	saved, err := store.Get(context.Background(), "s1")
+	require.NoError(t, err)
+	require.Len(t, saved.Turns, 1)
+
+	turn := saved.Turns[0]
+	assert.Equal(t, "When was Go released?", turn.Question)
+	assert.Equal(t, "Go was released in 2009.", turn.Answer)
+	assert.Equal(t, "big", turn.Model)
+	assert.Equal(t, FinalStatusNoToolsSelected, turn.FinalStatus)
+	assert.Empty(t, turn.Error)
+	assert.NotZero(t, turn.StartedAt)
+	assert.GreaterOrEqual(t, turn.CompletedAt, turn.StartedAt)
+
+	require.Len(t, turn.ToolCalls, 2)
+	search := turn.ToolCalls[0]
+	assert.Equal(t, "search", search.Name)
+	assert.Equal(t, map[string]any{"query": "go release"}, search.Arguments)
+	assert.Equal(t, 0, search.SelectionTurn)
+	assert.Empty(t, search.Error)
+	assert.NotZero(t, search.StartedAt)
+	require.Len(t, search.Results, 1)
+	assert.Equal(t, "https://en.wikipedia.org/wiki/Go_(programming_language)", search.Results[0].Attribution)
+	assert.Equal(t, []string{"Go was released in 2009."}, search.Results[0].Sentences)
+
+	missing := turn.ToolCalls[1]
+	assert.Equal(t, "missing", missing.Name)
+	assert.Contains(t, missing.Error, `Unknown tool "missing"`)
+
+	assert.Equal(t, []memory.UsageRecord{
+		{Role: UsageRoleBigModel, Model: "big", PromptTokens: 300, CompletionTokens: 20},
+		{Role: UsageRoleToolSelector, Model: "selector", PromptTokens: 200, CompletionTokens: 20},
+	}, turn.Usage)
+}
+
+func TestExecuteRecordsAnswerError(t *testing.T) {
+	conversations := memory.NewInMemoryStore(0)
+	agent := NewAgentBuilder().
+		WithBigModel(&testLLMClient{model: "big", shouldError: true, errorMessage: "model overloaded"}).
+		WithToolSelector(&testLLMClient{model: "selector"}).
+		WithConversationStore(conversations, 10).
+		Build()
+
+	_, err := agent.Execute(context.Background(), &MockProgressReporter{}, &schema.GenerateAnswerRequest{Question: "Hi", SessionId: "s1"})
+	require.ErrorIs(t, err, ErrInferenceFailed)
+
+	saved, err := conversations.Get(context.Background(), "s1")
+	require.NoError(t, err)
+	require.Len(t, saved.Turns, 1)
+	assert.Equal(t, "model overloaded", saved.Turns[0].Error)
+}
@@ -0,0 +1,217 @@
+package agentboot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SaiNageswarS/agent-boot/schema"
+	"github.com/ollama/ollama/api"
+)
+
+// NewTypedTool creates a tool builder whose parameter schema is derived from the fields of Args.
+// Arguments selected by the model are decoded into Args before fn runs.
+//
+// Field names come from the `json` tag and fields tagged `json:"-"` are skipped. Supported tags:
+//
+//	description:"..."  parameter description
+//	required:"true"    parameter must be provided
+//	enum:"a,b,c"       allowed values
+//	min:"1" max:"10"   inclusive bounds for numeric fields
+//	default:"5"        value used when the parameter is omitted
+//
+// Strings, booleans, integers, floats, slices, maps and nested structs are supported. time.Time
+// is an RFC 3339 string, []byte a base64 string, and types implementing json.Unmarshaler accept
+// any value. A struct nested in itself is described as an object without properties.
+// NewTypedTool panics if Args is not a struct, since that is a programming error.
+func NewTypedTool[Args any](name, description string, fn func(ctx context.Context, args Args) <-chan *schema.ToolResultChunk) *MCPToolBuilder {
+	t := reflect.TypeFor[Args]()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("agentboot: NewTypedTool %q requires a struct argument type, got %s", name, t))
+	}
+
+	b := NewMCPToolBuilder(name, description)
+	addStructFields(t, map[reflect.Type]bool{t: true}, b.setProp)
+
+	return b.WithHandler(func(ctx context.Context, params api.ToolCallFunctionArguments) <-chan *schema.ToolResultChunk {
+		var args Args
+		if err := decodeToolArguments(params, &args); err != nil {
+			ch := make(chan *schema.ToolResultChunk, 1)
+			ch <- NewToolResultChunk().Error(fmt.Sprintf("Invalid arguments: %v", err)).Build()
+			close(ch)
+			return ch
+		}
+		return fn(ctx, args)
+	})
+}
+
+// decodeToolArguments decodes tool call arguments into the struct pointed to by out.
+func decodeToolArguments(params api.ToolCallFunctionArguments, out any) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+var (
+	timeType        = reflect.TypeFor[time.Time]()
+	unmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+)
+
+// structSchema returns the JSON schema of an object with one property per exported field of t.
+// Embedded structs are flattened like encoding/json does. visiting holds the structs being
+// described, so a recursive type ends in an object without properties instead of looping.
+func structSchema(t reflect.Type, visiting map[reflect.Type]bool) map[string]any {
+	if visiting[t] {
+		return TypeSchema("object")
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	obj := NewObjectSchema()
+	addStructFields(t, visiting, func(name string, schema map[string]any, required bool) {
+		obj.Param(name, schema, required)
+	})
+	return obj.Build()
+}
+
+// addStructFields calls add for each parameter derived from the fields of t, in field order.
+func addStructFields(t reflect.Type, visiting map[reflect.Type]bool, add func(name string, schema map[string]any, required bool)) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		// Exported fields of embedded structs are promoted, even when the struct type is unexported.
+		// A struct embedding itself adds no fields past the first level, like in encoding/json.
+		if field.Anonymous && fieldType.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			if !visiting[fieldType] {
+				visiting[fieldType] = true
+				addStructFields(fieldType, visiting, add)
+				delete(visiting, fieldType)
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+
+		schema := typeSchema(fieldType, visiting)
+		applyFieldTags(schema, field, fieldType)
+		add(name, schema, field.Tag.Get("required") == "true")
+	}
+}
+
+// typeSchema returns the JSON schema for a Go type, as encoding/json represents it.
+func typeSchema(t reflect.Type, visiting map[reflect.Type]bool) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return TypeSchema("string", func(schema map[string]any) { schema["format"] = "date-time" })
+	case reflect.PointerTo(t).Implements(unmarshalerType):
+		// The type decodes itself, so any value may be valid
+		return map[string]any{}
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return TypeSchema("string") // base64 encoded
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return TypeSchema("string")
+	case reflect.Bool:
+		return TypeSchema("boolean")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return TypeSchema("integer")
+	case reflect.Float32, reflect.Float64:
+		return TypeSchema("number")
+	case reflect.Slice, reflect.Array:
+		return ArraySchema(typeSchema(t.Elem(), visiting))
+	case reflect.Struct:
+		return structSchema(t, visiting)
+	case reflect.Map:
+		return TypeSchema("object")
+	}
+	return map[string]any{}
+}
+
+// applyFieldTags adds description, enum, bounds and default from struct tags to schema.
+func applyFieldTags(schema map[string]any, field reflect.StructField, fieldType reflect.Type) {
+	if desc := field.Tag.Get("description"); desc != "" {
+		schema["description"] = desc
+	}
+
+	// For slices the enum applies to the elements
+	target := schema
+	if items, ok := schema["items"].(map[string]any); ok {
+		target = items
+	}
+	if enum := field.Tag.Get("enum"); enum != "" {
+		values := strings.Split(enum, ",")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+		ParamEnum(values...)(target)
+	}
+
+	if v, err := strconv.ParseFloat(field.Tag.Get("min"), 64); err == nil {
+		ParamMin(v)(schema)
+	}
+	if v, err := strconv.ParseFloat(field.Tag.Get("max"), 64); err == nil {
+		ParamMax(v)(schema)
+	}
+
+	if def, ok := field.Tag.Lookup("default"); ok {
+		ParamDefault(parseDefault(def, fieldType))(schema)
+	}
+}
+
+// parseDefault converts a default tag value to the JSON type of the field.
+func parseDefault(value string, t reflect.Type) any {
+	switch t.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return value
+}
+
+// jsonFieldName returns the JSON name of a struct field and whether it is skipped.
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, false
+	}
+	return field.Name, false
+}
@@ -0,0 +1,224 @@
+package agentboot
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/SaiNageswarS/agent-boot/schema"
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type searchFilter struct {
+	Field string  `json:"field" description:"Field name" required:"true"`
+	Value float64 `json:"value"`
+}
+
+type pagination struct {
+	Page int `json:"page" min:"1" default:"1"`
+}
+
+type searchArgs struct {
+	Query    string         `json:"query" description:"Search query" required:"true"`
+	Limit    int            `json:"limit,omitempty" description:"Maximum results" min:"1" max:"50" default:"10"`
+	Exact    bool           `json:"exact"`
+	Sort     string         `json:"sort" enum:"asc, desc"`
+	Tags     []string       `json:"tags" enum:"news,blog"`
+	Filters  []searchFilter `json:"filters"`
+	Scores   [][]float64    `json:"scores"`
+	Internal string         `json:"-"`
+	Renamed  string
+	pagination
+	hidden string
+}
+
+func TestNewTypedToolSchema(t *testing.T) {
+	tool := NewTypedTool("search", "Search documents", func(ctx context.Context, args searchArgs) <-chan *schema.ToolResultChunk {
+		return nil
+	}).Build()
+
+	assert.Equal(t, "search", tool.Function.Name)
+	assert.Equal(t, "Search documents", tool.Function.Description)
+	assert.Equal(t, []string{"query"}, tool.Function.Parameters.Required)
+
+	props := tool.Function.Parameters.Properties
+	assert.Len(t, props, 9)
+	assert.NotContains(t, props, "Internal")
+	assert.NotContains(t, props, "hidden")
+	assert.Contains(t, props, "Renamed")
+	assert.Contains(t, props, "page") // embedded struct fields are flattened
+
+	assert.Equal(t, api.PropertyType{"string"}, props["query"].Type)
+	assert.Equal(t, "Search query", props["query"].Description)
+	assert.Equal(t, api.PropertyType{"integer"}, props["limit"].Type)
+	assert.Equal(t, api.PropertyType{"boolean"}, props["exact"].Type)
+	assert.Equal(t, []any{"asc", "desc"}, props["sort"].Enum)
+	assert.Equal(t, map[string]any{"type": "string", "enum": []any{"news", "blog"}}, props["tags"].Items)
+	assert.Equal(t, api.PropertyType{"array"}, props["scores"].Type)
+	assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"type": "number"}}, props["scores"].Items)
+
+	limit := schemaProperties(tool.InputSchema)["limit"].(map[string]any)
+	assert.Equal(t, 1.0, limit["minimum"])
+	assert.Equal(t, 50.0, limit["maximum"])
+	assert.Equal(t, 10, limit["default"])
+
+	filterItems := props["filters"].Items.(map[string]any)
+	assert.Equal(t, "object", filterItems["type"])
+	assert.Equal(t, []string{"field"}, filterItems["required"])
+}
+
+func TestNewTypedToolDecodesArguments(t *testing.T) {
+	var received searchArgs
+	tool := NewTypedTool("search", "Search documents", func(ctx context.Context, args searchArgs) <-chan *schema.ToolResultChunk {
+		received = args
+		ch := make(chan *schema.ToolResultChunk, 1)
+		ch <- NewToolResultChunk().Sentences("ok").Build()
+		close(ch)
+		return ch
+	}).Build()
+
+	agent := NewAgentBuilder().
+		WithToolSelector(&mockLLMClient{}).
+		AddTool(tool).
+		Build()
+
+	result, err := agent.RunTool(context.Background(), &MockProgressReporter{}, "q", &api.ToolCall{
+		Function: api.ToolCallFunction{
+			Name: "search",
+			Arguments: api.ToolCallFunctionArguments{
+				"query":   "golang",
+				"exact":   "true",
+				"tags":    "news",
+				"filters": []any{map[string]any{"field": "year", "value": "2024"}},
+			},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, result, "ok")
+	assert.Equal(t, "golang", received.Query)
+	assert.Equal(t, 10, received.Limit) // default applied
+	assert.True(t, received.Exact)
+	assert.Equal(t, []string{"news"}, received.Tags)
+	assert.Equal(t, []searchFilter{{Field: "year", Value: 2024}}, received.Filters)
+	assert.Equal(t, 1, received.Page)
+}
+
+func TestNewTypedToolDecodeError(t *testing.T) {
+	called := false
+	tool := NewTypedTool("search", "Search documents", func(ctx context.Context, args searchArgs) <-chan *schema.ToolResultChunk {
+		called = true
+		return nil
+	}).Build()
+
+	// Calling the handler directly bypasses schema validation
+	ch := tool.Handler(context.Background(), api.ToolCallFunctionArguments{"limit": "many"})
+
+	chunk := <-ch
+	assert.False(t, called)
+	assert.Contains(t, chunk.Error, "Invalid arguments")
+}
+
+type treeNode struct {
+	Name     string     `json:"name"`
+	Children []treeNode `json:"children"`
+	Parent   *treeNode  `json:"parent"`
+}
+
+type eventArgs struct {
+	At      time.Time       `json:"at" required:"true"`
+	Payload []byte          `json:"payload"`
+	Raw     json.RawMessage `json:"raw"`
+	Root    treeNode        `json:"root"`
+}
+
+func TestNewTypedToolSchemaSpecialTypes(t *testing.T) {
+	var received eventArgs
+	tool := NewTypedTool("event", "Record an event", func(ctx context.Context, args eventArgs) <-chan *schema.ToolResultChunk {
+		received = args
+		ch := make(chan *schema.ToolResultChunk, 1)
+		ch <- NewToolResultChunk().Sentences("ok").Build()
+		close(ch)
+		return ch
+	}).Build()
+
+	props := schemaProperties(tool.InputSchema)
+	assert.Equal(t, map[string]any{"type": "string", "format": "date-time"}, props["at"])
+	assert.Equal(t, map[string]any{"type": "string"}, props["payload"])
+	assert.Equal(t, map[string]any{}, props["raw"])
+
+	// A recursive type stops at the first repetition
+	root := props["root"].(map[string]any)
+	rootProps := schemaProperties(root)
+	assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"type": "object"}}, rootProps["children"])
+	assert.Equal(t, map[string]any{"type": "object"}, rootProps["parent"])
+
+	agent := NewAgentBuilder().
+		WithToolSelector(&mockLLMClient{}).
+		AddTool(tool).
+		Build()
+
+	result, err := agent.RunTool(context.Background(), &MockProgressReporter{}, "q", &api.ToolCall{
+		Function: api.ToolCallFunction{
+			Name: "event",
+			Arguments: api.ToolCallFunctionArguments{
+				"at":      "2024-05-01T10:00:00Z",
+				"payload": "aGVsbG8=",
+				"raw":     map[string]any{"any": []any{1, "thing"}},
+				"root":    map[string]any{"name": "a", "children": []any{map[string]any{"name": "b"}}},
+			},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, result, "ok")
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), received.At)
+	assert.Equal(t, []byte("hello"), received.Payload)
+	assert.JSONEq(t, `{"any":[1,"thing"]}`, string(received.Raw))
+	assert.Equal(t, treeNode{Name: "a", Children: []treeNode{{Name: "b"}}}, received.Root)
+}
+
+func TestNewTypedToolRecursiveArgs(t *testing.T) {
+	tool := NewTypedTool("tree", "Walk a tree", func(ctx context.Context, args treeNode) <-chan *schema.ToolResultChunk {
+		return nil
+	}).Build()
+
+	assert.Equal(t, map[string]any{"type": "object"}, schemaProperties(tool.InputSchema)["parent"])
+}
+
+type selfEmbedding struct {
+	*selfEmbedding
+	Name string `json:"name"`
+}
+
+type embedsSelfEmbedding struct {
+	selfEmbedding
+	Extra int `json:"extra"`
+}
+
+func TestNewTypedToolSelfEmbeddingArgs(t *testing.T) {
+	tool := NewTypedTool("node", "Self embedding node", func(ctx context.Context, args selfEmbedding) <-chan *schema.ToolResultChunk {
+		return nil
+	}).Build()
+	assert.Equal(t, []string{"name"}, slices.Sorted(maps.Keys(schemaProperties(tool.InputSchema))))
+
+	tool = NewTypedTool("outer", "Embeds a self embedding node", func(ctx context.Context, args embedsSelfEmbedding) <-chan *schema.ToolResultChunk {
+		return nil
+	}).Build()
+	assert.Equal(t, []string{"extra", "name"}, slices.Sorted(maps.Keys(schemaProperties(tool.InputSchema))))
+
+	assert.Equal(t, map[string]any{"type": "object", "properties": map[string]any{"name": map[string]any{"type": "string"}}}, SchemaOf[selfEmbedding]())
+}
+
+func TestNewTypedToolRequiresStruct(t *testing.T) {
+	assert.Panics(t, func() {
+		NewTypedTool("bad", "Bad tool", func(ctx context.Context, args string) <-chan *schema.ToolResultChunk {
+			return nil
+		})
+	})
+}
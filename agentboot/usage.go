@@ -0,0 +1,179 @@
+package agentboot
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/SaiNageswarS/agent-boot/memory"
+	"github.com/SaiNageswarS/agent-boot/schema"
+)
+
+// Model roles whose token usage is reported separately in StreamComplete.Metadata.
+const (
+	UsageRoleToolSelector = "tool_selector"
+	UsageRoleMiniModel    = "mini_model"
+	UsageRoleBigModel     = "big_model"
+)
+
+// Metadata keys of StreamComplete.Metadata describing token usage and cost. Per-role keys are
+// prefixed with "usage.<role>.", e.g. "usage.big_model.prompt_tokens".
+const (
+	MetadataPromptTokens     = "prompt_tokens"
+	MetadataCompletionTokens = "completion_tokens"
+	MetadataTotalTokens      = "total_tokens"
+	MetadataCostUSD          = "cost_usd" // only reported when every model used has a price
+)
+
+// ModelPrice is the price of a model in US dollars per million tokens.
+type ModelPrice struct {
+	InputPerMillion  float64
+	OutputPerMillion float64
+}
+
+// Cost returns the price of usage in US dollars.
+func (p ModelPrice) Cost(usage llm.Usage) float64 {
+	return (float64(usage.PromptTokens)*p.InputPerMillion + float64(usage.CompletionTokens)*p.OutputPerMillion) / 1e6
+}
+
+// usageTracker aggregates the token usage of the LLM calls made for one request.
+// Tool results are summarized concurrently, so it is safe for concurrent use.
+type usageTracker struct {
+	mu     sync.Mutex
+	byRole map[string]*roleUsage
+}
+
+type roleUsage struct {
+	usage     llm.Usage
+	byModel   map[string]llm.Usage
+	lastModel string
+}
+
+func newUsageTracker() *usageTracker {
+	return &usageTracker{byRole: map[string]*roleUsage{}}
+}
+
+// recorder returns a usage callback recording the calls made with client in role. Usage is
+// charged to the model that served each call, falling back to the model of client. It returns nil
+// when usage is not tracked, e.g. when SelectTools is called outside of Execute.
+func (t *usageTracker) recorder(role string, client llm.LLMClient) func(llm.Usage) {
+	if t == nil || client == nil {
+		return nil
+	}
+
+	clientModel := client.GetModel()
+	return func(usage llm.Usage) {
+		model := usage.Model
+		if model == "" {
+			model = clientModel
+		}
+		usage.Model = ""
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		r, ok := t.byRole[role]
+		if !ok {
+			r = &roleUsage{byModel: map[string]llm.Usage{}}
+			t.byRole[role] = r
+		}
+		r.usage = r.usage.Add(usage)
+		r.byModel[model] = r.byModel[model].Add(usage)
+		r.lastModel = model
+	}
+}
+
+// lastModel returns the model that served the latest call recorded in role, or "" without one.
+func (t *usageTracker) lastModel(role string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if r, ok := t.byRole[role]; ok {
+		return r.lastModel
+	}
+	return ""
+}
+
+// report writes the totals, the per-role usage and, when prices are known, the cost to response.
+func (t *usageTracker) report(response *schema.StreamComplete, prices map[string]ModelPrice) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	roles := make([]string, 0, len(t.byRole))
+	for role := range t.byRole {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
+	var total llm.Usage
+	var totalCost float64
+	allPriced := len(roles) > 0
+	for _, role := range roles {
+		r := t.byRole[role]
+		total = total.Add(r.usage)
+		writeUsageMetadata(response.Metadata, "usage."+role+".", r.usage)
+
+		cost, priced := usageCost(r.byModel, prices)
+		if priced {
+			response.Metadata["usage."+role+"."+MetadataCostUSD] = formatCost(cost)
+			totalCost += cost
+		}
+		allPriced = allPriced && priced
+	}
+
+	response.TokenUsed = int32(total.TotalTokens())
+	writeUsageMetadata(response.Metadata, "", total)
+	if allPriced {
+		response.Metadata[MetadataCostUSD] = formatCost(totalCost)
+	}
+}
+
+// records returns the usage per role and model, sorted by role and model.
+func (t *usageTracker) records() []memory.UsageRecord {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var records []memory.UsageRecord
+	for role, r := range t.byRole {
+		for model, usage := range r.byModel {
+			records = append(records, memory.UsageRecord{
+				Role:             role,
+				Model:            model,
+				PromptTokens:     usage.PromptTokens,
+				CompletionTokens: usage.CompletionTokens,
+			})
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Role != records[j].Role {
+			return records[i].Role < records[j].Role
+		}
+		return records[i].Model < records[j].Model
+	})
+	return records
+}
+
+// usageCost returns the cost of the usage per model and whether every model has a price.
+func usageCost(byModel map[string]llm.Usage, prices map[string]ModelPrice) (float64, bool) {
+	var cost float64
+	for model, usage := range byModel {
+		price, ok := prices[model]
+		if !ok {
+			return 0, false
+		}
+		cost += price.Cost(usage)
+	}
+	return cost, true
+}
+
+func writeUsageMetadata(metadata map[string]string, prefix string, usage llm.Usage) {
+	metadata[prefix+MetadataPromptTokens] = strconv.Itoa(usage.PromptTokens)
+	metadata[prefix+MetadataCompletionTokens] = strconv.Itoa(usage.CompletionTokens)
+	metadata[prefix+MetadataTotalTokens] = strconv.Itoa(usage.TotalTokens())
+}
+
+func formatCost(cost float64) string {
+	return fmt.Sprintf("%.6f", cost)
+}
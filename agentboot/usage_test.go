@@ -0,0 +1,121 @@
+package agentboot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/SaiNageswarS/agent-boot/memory"
+	"github.com/SaiNageswarS/agent-boot/schema"
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentExecuteReportsUsage(t *testing.T) {
+	selector := &testLLMClient{
+		model:            "selector-model",
+		toolCallsPerTurn: [][]api.ToolCall{{{Function: api.ToolCallFunction{Name: "search", Arguments: api.ToolCallFunctionArguments{"query": "go"}}}}},
+		usagePerCall:     llm.Usage{PromptTokens: 100, CompletionTokens: 10},
+	}
+	miniModel := &testLLMClient{
+		model:        "mini-model",
+		response:     "Go is a programming language",
+		usagePerCall: llm.Usage{PromptTokens: 50, CompletionTokens: 5},
+	}
+	bigModel := &testLLMClient{
+		model:        "big-model",
+		response:     "Go is a language by Google",
+		usagePerCall: llm.Usage{PromptTokens: 300, CompletionTokens: 40},
+	}
+
+	tool := NewMCPToolBuilder("search", "Search the web").
+		StringParam("query", "Search query", true).
+		Summarize(true).
+		WithHandler(func(ctx context.Context, params api.ToolCallFunctionArguments) <-chan *schema.ToolResultChunk {
+			ch := make(chan *schema.ToolResultChunk, 2)
+			ch <- NewToolResultChunk().Title("Go").Sentences("Go is an open source language.").Build()
+			ch <- NewToolResultChunk().Title("Gophers").Sentences("Gophers are rodents.").Build()
+			close(ch)
+			return ch
+		}).
+		Build()
+
+	agent := NewAgentBuilder().
+		WithToolSelector(selector).
+		WithMiniModel(miniModel).
+		WithBigModel(bigModel).
+		AddTool(tool).
+		WithMaxTurns(3).
+		WithModelPrice("selector-model", ModelPrice{InputPerMillion: 1, OutputPerMillion: 2}).
+		WithModelPrice("mini-model", ModelPrice{InputPerMillion: 0.5, OutputPerMillion: 1}).
+		WithModelPrice("big-model", ModelPrice{InputPerMillion: 3, OutputPerMillion: 15}).
+		Build()
+
+	result, err := agent.Execute(context.Background(), &MockProgressReporter{}, &schema.GenerateAnswerRequest{Question: "What is Go?"})
+	require.NoError(t, err)
+
+	// Two tool selection turns, two summarized chunks and one answer
+	assert.Equal(t, int32(2*110+2*55+340), result.TokenUsed)
+	assert.Equal(t, "200", result.Metadata["usage.tool_selector.prompt_tokens"])
+	assert.Equal(t, "10", result.Metadata["usage.mini_model.completion_tokens"])
+	assert.Equal(t, "340", result.Metadata["usage.big_model.total_tokens"])
+	assert.Equal(t, "600", result.Metadata[MetadataPromptTokens])
+	assert.Equal(t, "70", result.Metadata[MetadataCompletionTokens])
+	assert.Equal(t, "670", result.Metadata[MetadataTotalTokens])
+
+	// (200*1 + 20*2) + (100*0.5 + 10*1) + (300*3 + 40*15) per million tokens
+	assert.Equal(t, "0.001800", result.Metadata[MetadataCostUSD])
+	assert.Equal(t, "0.001500", result.Metadata["usage.big_model.cost_usd"])
+}
+
+func TestAgentExecuteChargesUsageToFallbackModel(t *testing.T) {
+	primary := &testLLMClient{model: "primary-model", shouldError: true, errorMessage: "overloaded"}
+	fallback := &testLLMClient{
+		model:        "fallback-model",
+		response:     "Go is a language by Google",
+		usagePerCall: llm.Usage{PromptTokens: 1000, CompletionTokens: 100},
+	}
+	store := memory.NewInMemoryStore(0)
+
+	agent := NewAgentBuilder().
+		WithToolSelector(&testLLMClient{model: "selector-model"}).
+		WithBigModel(llm.NewFallbackClient(primary, fallback)).
+		WithConversationStore(store, 10).
+		WithModelPrice("selector-model", ModelPrice{}).
+		WithModelPrice("primary-model", ModelPrice{InputPerMillion: 100, OutputPerMillion: 100}).
+		WithModelPrice("fallback-model", ModelPrice{InputPerMillion: 1, OutputPerMillion: 10}).
+		Build()
+
+	result, err := agent.Execute(context.Background(), &MockProgressReporter{}, &schema.GenerateAnswerRequest{Question: "What is Go?", SessionId: "s1"})
+	require.NoError(t, err)
+	agent.Wait()
+
+	// 1000*1 + 100*10 per million tokens at the fallback price
+	assert.Equal(t, "0.002000", result.Metadata["usage.big_model.cost_usd"])
+
+	saved, err := store.Get(context.Background(), "s1")
+	require.NoError(t, err)
+	require.Len(t, saved.Turns, 1)
+	assert.Equal(t, "fallback-model", saved.Turns[0].Model)
+	assert.Contains(t, saved.Turns[0].Usage, memory.UsageRecord{Role: UsageRoleBigModel, Model: "fallback-model", PromptTokens: 1000, CompletionTokens: 100})
+}
+
+func TestUsageTrackerWithoutPrices(t *testing.T) {
+	tracker := newUsageTracker()
+	tracker.recorder(UsageRoleBigModel, &testLLMClient{model: "priced"})(llm.Usage{PromptTokens: 1000, CompletionTokens: 1000})
+	tracker.recorder(UsageRoleToolSelector, &testLLMClient{model: "unpriced"})(llm.Usage{PromptTokens: 10})
+
+	response := &schema.StreamComplete{Metadata: map[string]string{}}
+	tracker.report(response, map[string]ModelPrice{"priced": {InputPerMillion: 1, OutputPerMillion: 1}})
+
+	assert.Equal(t, int32(2010), response.TokenUsed)
+	assert.Equal(t, "0.002000", response.Metadata["usage.big_model.cost_usd"])
+	assert.NotContains(t, response.Metadata, "usage.tool_selector.cost_usd")
+	assert.NotContains(t, response.Metadata, MetadataCostUSD) // incomplete prices give no total
+}
+
+func TestUsageTrackerNil(t *testing.T) {
+	var tracker *usageTracker
+	assert.Nil(t, tracker.recorder(UsageRoleBigModel, &testLLMClient{}))
+}
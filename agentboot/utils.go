@@ -1,6 +1,8 @@
 package agentboot
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/ollama/ollama/api"
@@ -21,6 +23,15 @@ func findMCPToolByName(tools []MCPTool, name string) *MCPTool {
 	return nil
 }
 
+// toolNames returns the function names of the given tools
+func toolNames(tools []MCPTool) []string {
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Function.Name
+	}
+	return names
+}
+
 // toAPITools converts MCPTools to api.Tools for native tool calling
 func toAPITools(tools []MCPTool) []api.Tool {
 	apiTools := make([]api.Tool, len(tools))
@@ -29,3 +40,39 @@ func toAPITools(tools []MCPTool) []api.Tool {
 	}
 	return apiTools
 }
+
+// toolSchemas returns the complete parameter schemas of the tools that have one, by tool name, for
+// providers that can send them in place of the api.Tool parameters.
+func toolSchemas(tools []MCPTool) map[string]map[string]any {
+	schemas := make(map[string]map[string]any, len(tools))
+	for _, tool := range tools {
+		if tool.InputSchema != nil {
+			schemas[tool.Function.Name] = tool.InputSchema
+		}
+	}
+	return schemas
+}
+
+// toolCallKey identifies a tool call by its name and arguments.
+// json.Marshal sorts map keys, so equal arguments produce equal keys.
+func toolCallKey(call api.ToolCall) string {
+	args, err := json.Marshal(call.Function.Arguments)
+	if err != nil {
+		args = []byte(fmt.Sprintf("%v", call.Function.Arguments))
+	}
+	return call.Function.Name + ":" + string(args)
+}
+
+// dropRepeatedToolCalls returns the calls not present in seen and records them in seen.
+func dropRepeatedToolCalls(calls []api.ToolCall, seen map[string]bool) []api.ToolCall {
+	fresh := make([]api.ToolCall, 0, len(calls))
+	for _, call := range calls {
+		key := toolCallKey(call)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		fresh = append(fresh, call)
+	}
+	return fresh
+}
@@ -327,3 +327,24 @@ func BenchmarkToAPITools(b *testing.B) {
 		_ = apiTools
 	}
 }
+
+func TestDropRepeatedToolCalls(t *testing.T) {
+	seen := map[string]bool{}
+	first := []api.ToolCall{
+		{Function: api.ToolCallFunction{Name: "search", Arguments: map[string]any{"query": "a", "limit": 5}}},
+		{Function: api.ToolCallFunction{Name: "search", Arguments: map[string]any{"query": "b"}}},
+	}
+
+	fresh := dropRepeatedToolCalls(first, seen)
+	assert.Len(t, fresh, 2)
+
+	// Same name and arguments (in any key order) are dropped; new arguments are kept
+	second := []api.ToolCall{
+		{Function: api.ToolCallFunction{Name: "search", Arguments: map[string]any{"limit": 5, "query": "a"}}},
+		{Function: api.ToolCallFunction{Name: "search", Arguments: map[string]any{"query": "c"}}},
+	}
+
+	fresh = dropRepeatedToolCalls(second, seen)
+	assert.Len(t, fresh, 1)
+	assert.Equal(t, "c", fresh[0].Function.Arguments["query"])
+}
@@ -0,0 +1,132 @@
+// Command agentboot-server serves an agent over gRPC, with server reflection and the standard
+// gRPC health service.
+//
+// Usage:
+//
+//	agentboot-server -provider anthropic -model claude-sonnet-4-20250514 -addr :50051
+//
+// Provider credentials are read from the environment: OLLAMA_HOST, ANTHROPIC_API_KEY,
+// GROQ_API_KEY or OPENAI_API_KEY.
+//
+// Sessions are kept in memory by default, or as files with -sessions file -session-dir DIR. They
+// belong to the user in the x-user-id call metadata, which this server does not authenticate:
+// deploy it behind a proxy that authenticates callers and sets the header.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/SaiNageswarS/agent-boot/agentboot"
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/SaiNageswarS/agent-boot/memory"
+	"github.com/SaiNageswarS/agent-boot/schema"
+	"github.com/SaiNageswarS/go-api-boot/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+func main() {
+	addr := flag.String("addr", ":50051", "address to listen on")
+	provider := flag.String("provider", "ollama", "LLM provider: ollama, anthropic, groq or openai")
+	model := flag.String("model", "gpt-oss:20b", "model answering the questions and selecting tools")
+	baseURL := flag.String("base-url", "https://api.openai.com/v1", "base URL of the openai provider")
+	systemPrompt := flag.String("system-prompt", "You are a helpful assistant.", "system prompt of the agent")
+	maxTurns := flag.Int("max-turns", 5, "maximum tool calling turns per request")
+	maxTokens := flag.Int("max-tokens", 2000, "maximum tokens per answer")
+	sessions := flag.String("sessions", "memory", "where sessions are kept: memory, file or none")
+	sessionDir := flag.String("session-dir", "sessions", "directory of the sessions with -sessions file")
+	sessionTTL := flag.Duration("session-ttl", 24*time.Hour, "how long idle sessions are kept with -sessions memory")
+	sessionTurns := flag.Int("session-turns", 50, "user turns kept per session")
+	flag.Parse()
+
+	client, err := newLLMClient(*provider, *model, *baseURL)
+	if err != nil {
+		logger.Fatal("Invalid LLM configuration", zap.Error(err))
+	}
+
+	store, err := newSessionStore(*sessions, *sessionDir, *sessionTTL)
+	if err != nil {
+		logger.Fatal("Invalid session configuration", zap.Error(err))
+	}
+
+	builder := agentboot.NewAgentBuilder().
+		WithBigModel(client).
+		WithMiniModel(client).
+		WithToolSelector(client).
+		WithSystemPrompt(*systemPrompt).
+		WithMaxTurns(*maxTurns).
+		WithMaxTokens(*maxTokens)
+	if store != nil {
+		builder.WithConversationStore(store, *sessionTurns)
+	}
+	agent := builder.Build()
+
+	lis, err := net.Listen("tcp", *addr)
+	if err != nil {
+		logger.Fatal("Failed to listen", zap.String("addr", *addr), zap.Error(err))
+	}
+
+	server := grpc.NewServer()
+	schema.RegisterAgentServer(server, agentboot.NewGrpcServer(agent))
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	healthServer.SetServingStatus(schema.Agent_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	reflection.Register(server)
+
+	// Stop accepting requests on SIGINT or SIGTERM and let running ones finish
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		<-signals
+
+		logger.Info("Shutting down agent server")
+		healthServer.Shutdown()
+		server.GracefulStop()
+	}()
+
+	logger.Info("Agent server listening", zap.String("addr", lis.Addr().String()), zap.String("provider", *provider), zap.String("model", *model), zap.String("sessions", *sessions))
+	if err := server.Serve(lis); err != nil {
+		logger.Fatal("Agent server failed", zap.Error(err))
+	}
+
+	// Let the background work of the last requests finish
+	agent.Wait()
+}
+
+func newLLMClient(provider, model, baseURL string) (llm.LLMClient, error) {
+	switch provider {
+	case "ollama":
+		return llm.NewOllamaClient(model), nil
+	case "anthropic":
+		return llm.NewAnthropicClient(model), nil
+	case "groq":
+		return llm.NewGroqClient(model), nil
+	case "openai":
+		return llm.NewOpenAICompatibleClient(baseURL, os.Getenv("OPENAI_API_KEY"), model), nil
+	}
+	return nil, fmt.Errorf("unknown provider %q", provider)
+}
+
+// newSessionStore returns the store named by kind, or nil for none.
+func newSessionStore(kind, dir string, ttl time.Duration) (memory.Store, error) {
+	switch kind {
+	case "memory":
+		return memory.NewInMemoryStore(ttl), nil
+	case "file":
+		return memory.NewFileStore(dir)
+	case "none":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown session store %q", kind)
+}
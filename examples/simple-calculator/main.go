@@ -9,17 +9,14 @@ import (
 	"github.com/SaiNageswarS/agent-boot/agentboot"
 	"github.com/SaiNageswarS/agent-boot/llm"
 	"github.com/SaiNageswarS/agent-boot/schema"
-	"github.com/ollama/ollama/api"
 )
 
 func main() {
 	// Create LLM client (requires Ollama running locally)
 	llmClient := llm.NewOllamaClient("gpt-oss:20b")
 
-	// Create a simple calculator tool
-	calculatorTool := agentboot.NewMCPToolBuilder("calculator", "Performs basic mathematical calculations").
-		StringParam("expression", "Mathematical expression to evaluate (e.g., '2+2', '10*5')", true).
-		WithHandler(calculatorHandler).
+	// Create a simple calculator tool; the parameter schema is derived from calculatorArgs
+	calculatorTool := agentboot.NewTypedTool("calculator", "Performs basic mathematical calculations", calculatorHandler).
 		Build()
 
 	// Build the agent
@@ -52,21 +49,15 @@ func main() {
 	fmt.Printf("🔧 Tools Used: %v\n", response.ToolsUsed)
 }
 
-// calculatorHandler implements a simple calculator
-func calculatorHandler(ctx context.Context, params api.ToolCallFunctionArguments) <-chan *schema.ToolResultChunk {
-	ch := make(chan *schema.ToolResultChunk, 1)
-
-	go func() {
-		defer close(ch)
+// calculatorArgs are the calculator tool parameters
+type calculatorArgs struct {
+	Expression string `json:"expression" description:"Mathematical expression to evaluate (e.g., '2+2', '10*5')" required:"true"`
+}
 
-		expression, ok := params["expression"].(string)
-		if !ok {
-			chunk := agentboot.NewToolResultChunk().
-				Error("Invalid expression parameter").
-				Build()
-			ch <- chunk
-			return
-		}
+// calculatorHandler implements a simple calculator
+func calculatorHandler(ctx context.Context, args calculatorArgs) <-chan *schema.ToolResultChunk {
+	return agentboot.StreamToolResults(ctx, func(ctx context.Context, ch chan<- *schema.ToolResultChunk) {
+		expression := args.Expression
 
 		// Simple calculator implementation (you would use a proper math parser in production)
 		result, err := evaluateExpression(expression)
@@ -74,7 +65,7 @@ func calculatorHandler(ctx context.Context, params api.ToolCallFunctionArguments
 			chunk := agentboot.NewToolResultChunk().
 				Error(fmt.Sprintf("Calculation error: %v", err)).
 				Build()
-			ch <- chunk
+			agentboot.SendToolResult(ctx, ch, chunk)
 			return
 		}
 
@@ -84,10 +75,8 @@ func calculatorHandler(ctx context.Context, params api.ToolCallFunctionArguments
 			fmt.Sprintf("Result: %s", result),
 		})
 
-		ch <- chunk
-	}()
-
-	return ch
+		agentboot.SendToolResult(ctx, ch, chunk)
+	})
 }
 
 // Simple expression evaluator (very basic - you'd want a proper parser for production)
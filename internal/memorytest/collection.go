@@ -0,0 +1,176 @@
+// Package memorytest provides an in-memory conversation collection for the tests of this module,
+// which exercise a memory.ConversationManager without a database.
+package memorytest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/SaiNageswarS/agent-boot/memory"
+	"github.com/SaiNageswarS/go-api-boot/odm"
+	"github.com/SaiNageswarS/go-collection-boot/async"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Collection implements odm.OdmCollectionInterface[memory.Conversation] in memory. Filters may
+// compare "_id" and "userId" for equality, with nil matching an empty value, and sorting supports
+// "_id" and "updatedAt". Search and aggregation are not supported.
+type Collection struct {
+	mu       sync.Mutex
+	sessions map[string]memory.Conversation
+}
+
+func NewCollection(sessions ...memory.Conversation) *Collection {
+	c := &Collection{sessions: map[string]memory.Conversation{}}
+	for _, session := range sessions {
+		c.sessions[session.ID] = clone(session)
+	}
+	return c
+}
+
+func (c *Collection) Save(ctx context.Context, model memory.Conversation) <-chan async.Result[struct{}] {
+	return async.Go(func() (struct{}, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.sessions[model.ID] = clone(model)
+		return struct{}{}, nil
+	})
+}
+
+func (c *Collection) FindOneByID(ctx context.Context, id string) <-chan async.Result[*memory.Conversation] {
+	return c.FindOne(ctx, bson.M{"_id": id})
+}
+
+func (c *Collection) FindOne(ctx context.Context, filters bson.M) <-chan async.Result[*memory.Conversation] {
+	return async.Go(func() (*memory.Conversation, error) {
+		matches := c.find(filters)
+		if len(matches) == 0 {
+			return nil, mongo.ErrNoDocuments
+		}
+		return &matches[0], nil
+	})
+}
+
+func (c *Collection) Find(ctx context.Context, filters bson.M, sortBy bson.D, limit, skip int64) <-chan async.Result[[]memory.Conversation] {
+	return async.Go(func() ([]memory.Conversation, error) {
+		matches := c.find(filters)
+		sort.SliceStable(matches, func(i, j int) bool { return less(matches[i], matches[j], sortBy) })
+
+		matches = matches[min(int(skip), len(matches)):]
+		if limit > 0 && int(limit) < len(matches) {
+			matches = matches[:limit]
+		}
+		return matches, nil
+	})
+}
+
+func (c *Collection) DeleteByID(ctx context.Context, id string) <-chan async.Result[struct{}] {
+	return c.DeleteOne(ctx, bson.M{"_id": id})
+}
+
+func (c *Collection) DeleteOne(ctx context.Context, filters bson.M) <-chan async.Result[struct{}] {
+	return async.Go(func() (struct{}, error) {
+		matches := c.find(filters)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if len(matches) > 0 {
+			delete(c.sessions, matches[0].ID)
+		}
+		return struct{}{}, nil
+	})
+}
+
+func (c *Collection) Count(ctx context.Context, filters bson.M) <-chan async.Result[int64] {
+	return async.Go(func() (int64, error) {
+		return int64(len(c.find(filters))), nil
+	})
+}
+
+func (c *Collection) Exists(ctx context.Context, id string) <-chan async.Result[bool] {
+	return async.Go(func() (bool, error) {
+		return len(c.find(bson.M{"_id": id})) > 0, nil
+	})
+}
+
+func (c *Collection) DistinctInto(ctx context.Context, field string, filters bson.D, out any) error {
+	return errUnsupported
+}
+
+func (c *Collection) Aggregate(ctx context.Context, pipeline mongo.Pipeline) <-chan async.Result[[]memory.Conversation] {
+	return async.Go(func() ([]memory.Conversation, error) { return nil, errUnsupported })
+}
+
+func (c *Collection) VectorSearch(ctx context.Context, embedding []float32, opts odm.VectorSearchParams) <-chan async.Result[[]odm.SearchHit[memory.Conversation]] {
+	return async.Go(func() ([]odm.SearchHit[memory.Conversation], error) { return nil, errUnsupported })
+}
+
+func (c *Collection) TermSearch(ctx context.Context, query string, params odm.TermSearchParams) <-chan async.Result[[]odm.SearchHit[memory.Conversation]] {
+	return async.Go(func() ([]odm.SearchHit[memory.Conversation], error) { return nil, errUnsupported })
+}
+
+var errUnsupported = errors.New("memorytest: operation not supported")
+
+// find returns copies of the sessions matching filters, in ID order.
+func (c *Collection) find(filters bson.M) []memory.Conversation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var matches []memory.Conversation
+	for _, session := range c.sessions {
+		if matchesFilters(session, filters) {
+			matches = append(matches, clone(session))
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ID < matches[j].ID })
+	return matches
+}
+
+func matchesFilters(session memory.Conversation, filters bson.M) bool {
+	for key, want := range filters {
+		var got string
+		switch key {
+		case "_id":
+			got = session.ID
+		case "userId":
+			got = session.UserID
+		default:
+			return false
+		}
+
+		if want == nil {
+			want = ""
+		}
+		if got != want {
+			return false
+		}
+	}
+	return true
+}
+
+func less(a, b memory.Conversation, sortBy bson.D) bool {
+	for _, e := range sortBy {
+		desc := e.Value == -1
+		switch e.Key {
+		case "updatedAt":
+			if a.UpdatedAt != b.UpdatedAt {
+				return (a.UpdatedAt < b.UpdatedAt) != desc
+			}
+		case "_id":
+			if a.ID != b.ID {
+				return (a.ID < b.ID) != desc
+			}
+		}
+	}
+	return false
+}
+
+func clone(session memory.Conversation) memory.Conversation {
+	session.Messages = slices.Clone(session.Messages)
+	return session
+}
+
+var _ odm.OdmCollectionInterface[memory.Conversation] = (*Collection)(nil)
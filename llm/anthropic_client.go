@@ -8,19 +8,24 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strings"
 
 	"github.com/SaiNageswarS/go-api-boot/logger"
 	"github.com/ollama/ollama/api"
 )
 
 type AnthropicClient struct {
-	apiKey     string
-	httpClient *http.Client
-	url        string
-	model      string
+	apiKey       string
+	httpClient   *http.Client
+	url          string
+	model        string
+	headers      map[string]string
+	capabilities func(model string) Capability
+	retry        RetryPolicy
+	limiter      *RateLimiter
 }
 
-func NewAnthropicClient(model string) LLMClient {
+func NewAnthropicClient(model string, opts ...ClientOption) LLMClient {
 	apiKey := os.Getenv("ANTHROPIC_API_KEY")
 	if apiKey == "" {
 		// Providers are designed for dependency injection.
@@ -29,16 +34,24 @@ func NewAnthropicClient(model string) LLMClient {
 		return nil // This will never be reached, but it's good practice to return nil here.
 	}
 
+	o := newClientOptions(opts)
 	return &AnthropicClient{
-		apiKey:     apiKey,
-		httpClient: &http.Client{},
-		url:        "https://api.anthropic.com/v1/messages",
-		model:      model,
+		apiKey:       apiKey,
+		httpClient:   o.httpClient,
+		url:          "https://api.anthropic.com/v1/messages",
+		model:        model,
+		headers:      o.headers,
+		capabilities: o.capabilities,
+		retry:        o.retry,
+		limiter:      o.limiter,
 	}
 }
 
 func (c *AnthropicClient) Capabilities() Capability {
-	return 0 // Anthropic does not support native tool calling
+	if c.capabilities != nil {
+		return c.capabilities(c.model)
+	}
+	return NativeToolCalling
 }
 
 func (c *AnthropicClient) GetModel() string {
@@ -46,6 +59,43 @@ func (c *AnthropicClient) GetModel() string {
 }
 
 func (c *AnthropicClient) GenerateInference(ctx context.Context, messages []Message, callback func(chunk string) error, opts ...LLMOption) error {
+	settings := c.settings(opts)
+
+	blocks, err := c.generate(ctx, settings, c.buildRequest(settings, messages, nil), callback)
+	if err != nil {
+		return err
+	}
+
+	if len(blocks) == 0 {
+		return fmt.Errorf("no content in response")
+	}
+	return nil
+}
+
+func (c *AnthropicClient) GenerateInferenceWithTools(
+	ctx context.Context,
+	messages []Message,
+	contentCallback func(chunk string) error,
+	toolCallback func(toolCalls []api.ToolCall) error,
+	opts ...LLMOption,
+) error {
+	settings := c.settings(opts)
+
+	blocks, err := c.generate(ctx, settings, c.buildRequest(settings, messages, convertToolsToAnthropicFormat(settings.tools, settings.toolSchemas)), contentCallback)
+	if err != nil {
+		return err
+	}
+
+	// Handle tool calls
+	_, toolCalls := parseAnthropicContent(blocks)
+	if len(toolCalls) > 0 && toolCallback != nil {
+		return toolCallback(toolCalls)
+	}
+
+	return nil
+}
+
+func (c *AnthropicClient) settings(opts []LLMOption) LLMSettings {
 	settings := LLMSettings{
 		model:       c.model,
 		temperature: 0.7,
@@ -56,86 +106,375 @@ func (c *AnthropicClient) GenerateInference(ctx context.Context, messages []Mess
 	for _, opt := range opts {
 		opt(&settings)
 	}
+	return settings
+}
+
+func (c *AnthropicClient) buildRequest(settings LLMSettings, messages []Message, tools []anthropicTool) anthropicRequest {
+	if len(tools) == 0 {
+		// Anthropic rejects tool_use and tool_result blocks in requests without tools
+		messages = flattenToolMessages(messages)
+	}
 
-	request := anthropicRequest{
+	return anthropicRequest{
 		Model:       settings.model,
 		MaxTokens:   settings.maxTokens,
 		Temperature: settings.temperature,
 		System:      settings.system,
-		Messages:    messages,
+		Messages:    convertMessagesToAnthropicFormat(messages),
+		Tools:       tools,
+		Stream:      settings.stream,
 	}
+}
 
-	jsonData, err := json.Marshal(request)
+// generate sends the request and returns the content blocks of the response. Text is passed to
+// contentCallback as it arrives when streaming, or in a single call otherwise.
+func (c *AnthropicClient) generate(ctx context.Context, settings LLMSettings, request anthropicRequest, contentCallback func(chunk string) error) ([]anthropicContent, error) {
+	resp, err := c.send(ctx, request)
 	if err != nil {
-		return fmt.Errorf("error marshaling request: %w", err)
+		return nil, err
 	}
+	defer resp.Body.Close()
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("error creating request: %w", err)
+	if request.Stream {
+		blocks, usage, err := readAnthropicStream(resp.Body, contentCallback)
+		if err == nil {
+			settings.ReportUsage(usage)
+		}
+		return blocks, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", c.apiKey)
-	req.Header.Set("anthropic-version", "2023-06-01")
+	var response anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %w", err)
+	}
+	settings.ReportUsage(response.Usage.toUsage())
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error making request: %w", err)
+	// Handle regular content
+	if text, _ := parseAnthropicContent(response.Content); text != "" && contentCallback != nil {
+		if err := contentCallback(text); err != nil {
+			return nil, err
+		}
 	}
-	defer resp.Body.Close()
+	return response.Content, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
+// send posts the request, retrying transient failures, and returns the response for the caller to close.
+func (c *AnthropicClient) send(ctx context.Context, request anthropicRequest) (*http.Response, error) {
+	jsonData, err := json.Marshal(request)
 	if err != nil {
-		return fmt.Errorf("error reading response: %w", err)
+		return nil, fmt.Errorf("error marshaling request: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	return doWithRetry(ctx, c.httpClient, c.retry, c.limiter, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", c.url, bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("x-api-key", c.apiKey)
+		req.Header.Set("anthropic-version", "2023-06-01")
+		if request.Stream {
+			req.Header.Set("Accept", "text/event-stream")
+		}
+		setHeaders(req, c.headers)
+		return req, nil
+	})
+}
+
+// readAnthropicStream assembles the content blocks and usage of a streamed response. Text deltas are
+// passed to contentCallback as they arrive and tool_use input is assembled from its partial JSON deltas.
+func readAnthropicStream(body io.Reader, contentCallback func(chunk string) error) ([]anthropicContent, Usage, error) {
+	var blocks []anthropicContent
+	var partialInputs []strings.Builder
+	var usage Usage
+
+	err := readSSE(body, func(_, data string) error {
+		var event anthropicStreamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return fmt.Errorf("error unmarshaling stream event: %w", err)
+		}
+
+		switch event.Type {
+		case "message_start":
+			usage.PromptTokens = event.Message.Usage.InputTokens
+			usage.CompletionTokens = event.Message.Usage.OutputTokens
+
+		case "message_delta":
+			// Output tokens are cumulative
+			usage.CompletionTokens = event.Usage.OutputTokens
+
+		case "content_block_start":
+			for len(blocks) <= event.Index {
+				blocks = append(blocks, anthropicContent{})
+				partialInputs = append(partialInputs, strings.Builder{})
+			}
+			blocks[event.Index] = event.ContentBlock
+
+		case "content_block_delta":
+			if event.Index >= len(blocks) {
+				return fmt.Errorf("stream delta for unknown content block %d", event.Index)
+			}
+			switch event.Delta.Type {
+			case "text_delta":
+				blocks[event.Index].Text += event.Delta.Text
+				if event.Delta.Text != "" && contentCallback != nil {
+					return contentCallback(event.Delta.Text)
+				}
+			case "input_json_delta":
+				partialInputs[event.Index].WriteString(event.Delta.PartialJSON)
+			}
+
+		case "content_block_stop":
+			if event.Index < len(blocks) && partialInputs[event.Index].Len() > 0 {
+				var input map[string]any
+				if err := json.Unmarshal([]byte(partialInputs[event.Index].String()), &input); err != nil {
+					return fmt.Errorf("error parsing tool_use input: %w", err)
+				}
+				blocks[event.Index].Input = input
+			}
+
+		case "message_stop":
+			return io.EOF
+
+		case "error":
+			return fmt.Errorf("stream error: %s: %s", event.Error.Type, event.Error.Message)
+		}
+		return nil
+	})
+
+	return blocks, usage, err
+}
+
+// parseAnthropicContent joins the text blocks and converts tool_use blocks to Ollama tool calls.
+func parseAnthropicContent(blocks []anthropicContent) (string, []api.ToolCall) {
+	var text strings.Builder
+	var toolCalls []api.ToolCall
+
+	for _, block := range blocks {
+		switch block.Type {
+		case "text":
+			text.WriteString(block.Text)
+		case "tool_use":
+			args, _ := block.Input.(map[string]any)
+			toolCalls = append(toolCalls, api.ToolCall{
+				Function: api.ToolCallFunction{
+					Name:      block.Name,
+					Arguments: args,
+				},
+			})
+		}
 	}
 
-	var response anthropicResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return fmt.Errorf("error unmarshaling response: %w", err)
+	return text.String(), toolCalls
+}
+
+// convertMessagesToAnthropicFormat converts messages to Anthropic content blocks.
+// Assistant tool calls become tool_use blocks and "tool" messages become tool_result blocks
+// in a user message. Tool calls get positional IDs, which tool messages without a ToolCallID
+// are matched against in order. Consecutive messages with the same role are merged, since
+// Anthropic expects user and assistant turns to alternate.
+func convertMessagesToAnthropicFormat(messages []Message) []anthropicMessage {
+	var result []anthropicMessage
+	var pendingIDs []string
+
+	for i, msg := range messages {
+		role := msg.Role
+		var blocks []anthropicContent
+
+		switch {
+		case msg.Role == "tool":
+			role = "user"
+			id := msg.ToolCallID
+			if id == "" && len(pendingIDs) > 0 {
+				id, pendingIDs = pendingIDs[0], pendingIDs[1:]
+			}
+			if id == "" {
+				// A result without a matching call can only be passed as text
+				blocks = append(blocks, anthropicContent{Type: "text", Text: msg.Content})
+				break
+			}
+			blocks = append(blocks, anthropicContent{Type: "tool_result", ToolUseID: id, Content: msg.Content})
+
+		case msg.Role == "assistant" && len(msg.ToolCalls) > 0:
+			if msg.Content != "" {
+				blocks = append(blocks, anthropicContent{Type: "text", Text: msg.Content})
+			}
+			pendingIDs = pendingIDs[:0]
+			for j, call := range msg.ToolCalls {
+				id := anthropicToolUseID(i, j)
+				pendingIDs = append(pendingIDs, id)
+
+				input := map[string]any(call.Function.Arguments)
+				if input == nil {
+					input = map[string]any{}
+				}
+				blocks = append(blocks, anthropicContent{Type: "tool_use", ID: id, Name: call.Function.Name, Input: input})
+			}
+
+		default:
+			if msg.Content == "" {
+				continue // Anthropic rejects empty text blocks
+			}
+			blocks = append(blocks, anthropicContent{Type: "text", Text: msg.Content})
+		}
+
+		if n := len(result); n > 0 && result[n-1].Role == role {
+			result[n-1].Content = append(result[n-1].Content, blocks...)
+			continue
+		}
+		result = append(result, anthropicMessage{Role: role, Content: blocks})
 	}
 
-	if len(response.Content) == 0 {
-		return fmt.Errorf("no content in response")
+	return result
+}
+
+// flattenToolMessages rewrites assistant tool calls and "tool" results as plain text messages,
+// for requests that cannot carry them natively.
+func flattenToolMessages(messages []Message) []Message {
+	result := make([]Message, 0, len(messages))
+	for _, msg := range messages {
+		switch {
+		case msg.Role == "tool":
+			content := msg.Content
+			if msg.ToolName != "" {
+				content = fmt.Sprintf("Result of tool %s:\n%s", msg.ToolName, msg.Content)
+			}
+			result = append(result, Message{Role: "user", Content: content})
+
+		case len(msg.ToolCalls) > 0:
+			var text strings.Builder
+			text.WriteString(msg.Content)
+			for _, call := range msg.ToolCalls {
+				args, err := json.Marshal(call.Function.Arguments)
+				if err != nil || call.Function.Arguments == nil {
+					args = []byte("{}")
+				}
+				if text.Len() > 0 {
+					text.WriteString("\n")
+				}
+				fmt.Fprintf(&text, "Calling tool %s with arguments %s", call.Function.Name, args)
+			}
+			result = append(result, Message{Role: msg.Role, Content: text.String()})
+
+		default:
+			result = append(result, msg)
+		}
 	}
+	return result
+}
 
-	return callback(response.Content[0].Text)
+func anthropicToolUseID(msgIndex, callIndex int) string {
+	return fmt.Sprintf("toolu_%d_%d", msgIndex, callIndex)
 }
 
-func (c *AnthropicClient) GenerateInferenceWithTools(
-	ctx context.Context,
-	messages []Message,
-	contentCallback func(chunk string) error,
-	toolCallback func(toolCalls []api.ToolCall) error,
-	opts ...LLMOption,
-) error {
-	// Anthropic doesn't support native tool calling, so we just use regular inference
-	return c.GenerateInference(ctx, messages, contentCallback, opts...)
+// convertToolsToAnthropicFormat converts Ollama tools to Anthropic format, sending the schema of a
+// tool in schemas as its input schema when there is one
+func convertToolsToAnthropicFormat(tools []api.Tool, schemas map[string]map[string]any) []anthropicTool {
+	if len(tools) == 0 {
+		return nil
+	}
+
+	anthropicTools := make([]anthropicTool, len(tools))
+	for i, tool := range tools {
+		if schema, ok := schemas[tool.Function.Name]; ok {
+			anthropicTools[i] = anthropicTool{
+				Name:        tool.Function.Name,
+				Description: tool.Function.Description,
+				InputSchema: schema,
+			}
+			continue
+		}
+
+		// Anthropic requires a valid JSON schema object
+		params := tool.Function.Parameters
+		if params.Type == "" {
+			params.Type = "object"
+		}
+		if params.Properties == nil {
+			params.Properties = map[string]api.ToolProperty{}
+		}
+		if params.Required == nil {
+			params.Required = []string{}
+		}
+
+		anthropicTools[i] = anthropicTool{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description,
+			InputSchema: params,
+		}
+	}
+	return anthropicTools
 }
 
 type anthropicRequest struct {
-	Model       string    `json:"model"`
-	MaxTokens   int       `json:"max_tokens"`
-	Messages    []Message `json:"messages"`
-	System      string    `json:"system,omitempty"`
-	Temperature float64   `json:"temperature"`
+	Model       string             `json:"model"`
+	MaxTokens   int                `json:"max_tokens"`
+	Messages    []anthropicMessage `json:"messages"`
+	System      string             `json:"system,omitempty"`
+	Temperature float64            `json:"temperature"`
+	Tools       []anthropicTool    `json:"tools,omitempty"`
+	Stream      bool               `json:"stream,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string             `json:"role"`
+	Content []anthropicContent `json:"content"`
+}
+
+type anthropicTool struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	InputSchema interface{} `json:"input_schema"`
 }
 
 // anthropicResponse represents the response from Anthropic API
 type anthropicResponse struct {
-	Content []content `json:"content"`
-	ID      string    `json:"id"`
-	Model   string    `json:"model"`
-	Role    string    `json:"role"`
-	Type    string    `json:"type"`
+	Content    []anthropicContent `json:"content"`
+	ID         string             `json:"id"`
+	Model      string             `json:"model"`
+	Role       string             `json:"role"`
+	Type       string             `json:"type"`
+	StopReason string             `json:"stop_reason"`
+	Usage      anthropicUsage     `json:"usage"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+func (u anthropicUsage) toUsage() Usage {
+	return Usage{PromptTokens: u.InputTokens, CompletionTokens: u.OutputTokens}
+}
+
+// anthropicStreamEvent is a server-sent event of a streamed response
+type anthropicStreamEvent struct {
+	Type    string `json:"type"`
+	Index   int    `json:"index"`
+	Message struct {
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"`
+	Usage        anthropicUsage   `json:"usage"`
+	ContentBlock anthropicContent `json:"content_block"`
+	Delta        struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		PartialJSON string `json:"partial_json"`
+	} `json:"delta"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
 }
 
-// content represents the content in the response
-type content struct {
-	Text string `json:"text"`
-	Type string `json:"type"`
+// anthropicContent is a content block of a message: text, tool_use or tool_result
+type anthropicContent struct {
+	Type      string `json:"type"`
+	Text      string `json:"text,omitempty"`
+	ID        string `json:"id,omitempty"`          // tool_use
+	Name      string `json:"name,omitempty"`        // tool_use
+	Input     any    `json:"input,omitempty"`       // tool_use
+	ToolUseID string `json:"tool_use_id,omitempty"` // tool_result
+	Content   string `json:"content,omitempty"`     // tool_result
 }
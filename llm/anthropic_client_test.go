@@ -2,11 +2,14 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/ollama/ollama/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestProvideAnthropicClient_MissingAPIKey(t *testing.T) {
@@ -127,3 +130,297 @@ func TestGenerateInference_EmptyContent(t *testing.T) {
 	assert.Error(t, err)
 	assert.EqualError(t, err, "no content in response")
 }
+
+func TestAnthropicClientCapabilities(t *testing.T) {
+	client := &AnthropicClient{model: "claude-sonnet-4"}
+	assert.Equal(t, NativeToolCalling, client.Capabilities())
+}
+
+func TestGenerateInferenceWithTools_ToolUse(t *testing.T) {
+	mockResp := `{
+		"content": [
+			{"type": "text", "text": "Let me calculate that."},
+			{"type": "tool_use", "id": "toolu_01", "name": "calculator", "input": {"expression": "2+2"}}
+		],
+		"id": "id123", "model": "claude-sonnet-4", "role": "assistant", "type": "message", "stop_reason": "tool_use",
+		"usage": {"input_tokens": 120, "output_tokens": 30}
+	}`
+
+	var received anthropicRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(mockResp))
+	}))
+	defer server.Close()
+
+	client := &AnthropicClient{
+		apiKey:     "test-key",
+		httpClient: server.Client(),
+		url:        server.URL,
+		model:      "claude-sonnet-4",
+	}
+
+	tool := api.Tool{Type: "function"}
+	tool.Function.Name = "calculator"
+	tool.Function.Description = "Calculate mathematical expressions"
+	tool.Function.Parameters.Type = "object"
+	tool.Function.Parameters.Required = []string{"expression"}
+	tool.Function.Parameters.Properties = map[string]api.ToolProperty{
+		"expression": {Type: api.PropertyType{"string"}, Description: "Expression to evaluate"},
+	}
+
+	var content string
+	var toolCalls []api.ToolCall
+	var usage Usage
+	err := client.GenerateInferenceWithTools(
+		context.Background(),
+		[]Message{{Role: "user", Content: "Calculate 2+2"}},
+		func(chunk string) error {
+			content += chunk
+			return nil
+		},
+		func(calls []api.ToolCall) error {
+			toolCalls = calls
+			return nil
+		},
+		WithTools([]api.Tool{tool}),
+		WithUsageCallback(func(u Usage) { usage = u }),
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, Usage{PromptTokens: 120, CompletionTokens: 30, Model: "claude-sonnet-4"}, usage)
+	assert.Equal(t, "Let me calculate that.", content)
+	require.Len(t, toolCalls, 1)
+	assert.Equal(t, "calculator", toolCalls[0].Function.Name)
+	assert.Equal(t, "2+2", toolCalls[0].Function.Arguments["expression"])
+
+	require.Len(t, received.Tools, 1)
+	assert.Equal(t, "calculator", received.Tools[0].Name)
+	assert.Equal(t, "Calculate mathematical expressions", received.Tools[0].Description)
+	inputSchema := received.Tools[0].InputSchema.(map[string]any)
+	assert.Equal(t, "object", inputSchema["type"])
+	assert.Equal(t, []any{"expression"}, inputSchema["required"])
+	assert.Contains(t, inputSchema["properties"], "expression")
+}
+
+func TestGenerateInferenceWithTools_NoToolsOmitsField(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = w.Write([]byte(`{"content": [{"type": "text", "text": "Hi"}]}`))
+	}))
+	defer server.Close()
+
+	client := &AnthropicClient{apiKey: "test-key", httpClient: server.Client(), url: server.URL}
+
+	var content string
+	err := client.GenerateInferenceWithTools(context.Background(), []Message{{Role: "user", Content: "Hello"}},
+		func(chunk string) error {
+			content += chunk
+			return nil
+		},
+		func(calls []api.ToolCall) error {
+			t.Fatal("unexpected tool calls")
+			return nil
+		},
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", content)
+	assert.NotContains(t, body, "tools")
+}
+
+func TestConvertMessagesToAnthropicFormat_ToolResults(t *testing.T) {
+	messages := []Message{
+		{Role: "user", Content: "What is 2+2 and 3+3?"},
+		{
+			Role: "assistant",
+			ToolCalls: []api.ToolCall{
+				{Function: api.ToolCallFunction{Name: "calculator", Arguments: api.ToolCallFunctionArguments{"expression": "2+2"}}},
+				{Function: api.ToolCallFunction{Name: "calculator", Arguments: api.ToolCallFunctionArguments{"expression": "3+3"}}},
+			},
+		},
+		{Role: "tool", Content: "4"},
+		{Role: "tool", Content: "6", ToolCallID: "explicit_id"},
+		{Role: "user", Content: "Thanks"},
+		{Role: "assistant", Content: ""},
+	}
+
+	converted := convertMessagesToAnthropicFormat(messages)
+
+	require.Len(t, converted, 3)
+	assert.Equal(t, "user", converted[0].Role)
+
+	assert.Equal(t, "assistant", converted[1].Role)
+	require.Len(t, converted[1].Content, 2)
+	assert.Equal(t, "tool_use", converted[1].Content[0].Type)
+	assert.Equal(t, "calculator", converted[1].Content[0].Name)
+	assert.Equal(t, map[string]any{"expression": "2+2"}, converted[1].Content[0].Input)
+	firstID := converted[1].Content[0].ID
+	assert.NotEmpty(t, firstID)
+
+	// Tool results and the following user message are merged into one user turn
+	assert.Equal(t, "user", converted[2].Role)
+	require.Len(t, converted[2].Content, 3)
+	assert.Equal(t, anthropicContent{Type: "tool_result", ToolUseID: firstID, Content: "4"}, converted[2].Content[0])
+	assert.Equal(t, anthropicContent{Type: "tool_result", ToolUseID: "explicit_id", Content: "6"}, converted[2].Content[1])
+	assert.Equal(t, anthropicContent{Type: "text", Text: "Thanks"}, converted[2].Content[2])
+}
+
+func TestConvertMessagesToAnthropicFormat_UnmatchedToolResult(t *testing.T) {
+	converted := convertMessagesToAnthropicFormat([]Message{
+		{Role: "user", Content: "What is 2+2?"},
+		{Role: "tool", Content: "4"},
+	})
+
+	require.Len(t, converted, 1)
+	assert.Equal(t, []anthropicContent{
+		{Type: "text", Text: "What is 2+2?"},
+		{Type: "text", Text: "4"},
+	}, converted[0].Content)
+}
+
+func TestBuildRequest_FlattensToolMessagesWithoutTools(t *testing.T) {
+	client := &AnthropicClient{model: "claude-3"}
+	messages := []Message{
+		{Role: "user", Content: "What is 2+2?"},
+		{Role: "assistant", ToolCalls: []api.ToolCall{
+			{Function: api.ToolCallFunction{Name: "calculator", Arguments: api.ToolCallFunctionArguments{"expression": "2+2"}}},
+		}},
+		{Role: "tool", Content: "4", ToolName: "calculator"},
+	}
+
+	request := client.buildRequest(client.settings(nil), messages, nil)
+
+	require.Len(t, request.Messages, 3)
+	assert.Equal(t, []anthropicContent{{Type: "text", Text: `Calling tool calculator with arguments {"expression":"2+2"}`}}, request.Messages[1].Content)
+	assert.Equal(t, "user", request.Messages[2].Role)
+	assert.Equal(t, []anthropicContent{{Type: "text", Text: "Result of tool calculator:\n4"}}, request.Messages[2].Content)
+
+	// With tools the native blocks are kept
+	request = client.buildRequest(client.settings(nil), messages, convertToolsToAnthropicFormat([]api.Tool{{Function: api.ToolFunction{Name: "calculator"}}}, nil))
+	assert.Equal(t, "tool_use", request.Messages[1].Content[0].Type)
+	assert.Equal(t, "tool_result", request.Messages[2].Content[0].Type)
+}
+
+func TestConvertToolsToAnthropicFormat_UsesSchema(t *testing.T) {
+	tools := []api.Tool{{Function: api.ToolFunction{Name: "calculator"}}, {Function: api.ToolFunction{Name: "clock"}}}
+	schema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"precision": map[string]any{"type": "integer", "default": 2}},
+	}
+
+	converted := convertToolsToAnthropicFormat(tools, map[string]map[string]any{"calculator": schema})
+
+	require.Len(t, converted, 2)
+	assert.Equal(t, schema, converted[0].InputSchema)
+	data, err := json.Marshal(converted[1].InputSchema)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"type":"object"`)
+}
+
+func TestGenerateInference_Streaming(t *testing.T) {
+	stream := "event: message_start\n" +
+		`data: {"type": "message_start", "message": {"id": "msg_1", "role": "assistant", "content": [], "usage": {"input_tokens": 25, "output_tokens": 1}}}` + "\n\n" +
+		"event: content_block_start\n" +
+		`data: {"type": "content_block_start", "index": 0, "content_block": {"type": "text", "text": ""}}` + "\n\n" +
+		": keep-alive\n\n" +
+		"event: content_block_delta\n" +
+		`data: {"type": "content_block_delta", "index": 0, "delta": {"type": "text_delta", "text": "Hello"}}` + "\n\n" +
+		"event: content_block_delta\n" +
+		`data: {"type": "content_block_delta", "index": 0, "delta": {"type": "text_delta", "text": " world"}}` + "\n\n" +
+		"event: content_block_stop\n" +
+		`data: {"type": "content_block_stop", "index": 0}` + "\n\n" +
+		"event: message_delta\n" +
+		`data: {"type": "message_delta", "delta": {"stop_reason": "end_turn"}, "usage": {"output_tokens": 15}}` + "\n\n" +
+		"event: message_stop\n" +
+		`data: {"type": "message_stop"}` + "\n\n"
+
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(stream))
+	}))
+	defer server.Close()
+
+	client := &AnthropicClient{apiKey: "test-key", httpClient: server.Client(), url: server.URL}
+
+	var chunks []string
+	var usage Usage
+	err := client.GenerateInference(context.Background(), []Message{{Role: "user", Content: "Hi"}}, func(chunk string) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}, WithStreaming(true), WithUsageCallback(func(u Usage) { usage = u }))
+
+	require.NoError(t, err)
+	assert.Equal(t, Usage{PromptTokens: 25, CompletionTokens: 15}, usage)
+	assert.Equal(t, true, received["stream"])
+	assert.Equal(t, []string{"Hello", " world"}, chunks)
+}
+
+func TestGenerateInferenceWithTools_StreamingToolUse(t *testing.T) {
+	stream := "event: content_block_start\n" +
+		`data: {"type": "content_block_start", "index": 0, "content_block": {"type": "text", "text": ""}}` + "\n\n" +
+		"event: content_block_delta\n" +
+		`data: {"type": "content_block_delta", "index": 0, "delta": {"type": "text_delta", "text": "Calculating."}}` + "\n\n" +
+		"event: content_block_stop\n" +
+		`data: {"type": "content_block_stop", "index": 0}` + "\n\n" +
+		"event: content_block_start\n" +
+		`data: {"type": "content_block_start", "index": 1, "content_block": {"type": "tool_use", "id": "toolu_01", "name": "calculator", "input": {}}}` + "\n\n" +
+		"event: content_block_delta\n" +
+		`data: {"type": "content_block_delta", "index": 1, "delta": {"type": "input_json_delta", "partial_json": "{\"expression\": "}}` + "\n\n" +
+		"event: content_block_delta\n" +
+		`data: {"type": "content_block_delta", "index": 1, "delta": {"type": "input_json_delta", "partial_json": "\"2+2\"}"}}` + "\n\n" +
+		"event: content_block_stop\n" +
+		`data: {"type": "content_block_stop", "index": 1}` + "\n\n" +
+		"event: message_stop\n" +
+		`data: {"type": "message_stop"}` + "\n\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(stream))
+	}))
+	defer server.Close()
+
+	client := &AnthropicClient{apiKey: "test-key", httpClient: server.Client(), url: server.URL}
+
+	var content string
+	var toolCalls []api.ToolCall
+	err := client.GenerateInferenceWithTools(context.Background(), []Message{{Role: "user", Content: "2+2?"}},
+		func(chunk string) error {
+			content += chunk
+			return nil
+		},
+		func(calls []api.ToolCall) error {
+			toolCalls = calls
+			return nil
+		},
+		WithStreaming(true),
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Calculating.", content)
+	require.Len(t, toolCalls, 1)
+	assert.Equal(t, "calculator", toolCalls[0].Function.Name)
+	assert.Equal(t, "2+2", toolCalls[0].Function.Arguments["expression"])
+}
+
+func TestGenerateInference_StreamingError(t *testing.T) {
+	stream := "event: error\n" +
+		`data: {"type": "error", "error": {"type": "overloaded_error", "message": "Overloaded"}}` + "\n\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(stream))
+	}))
+	defer server.Close()
+
+	client := &AnthropicClient{apiKey: "test-key", httpClient: server.Client(), url: server.URL}
+
+	err := client.GenerateInference(context.Background(), []Message{{Role: "user", Content: "Hi"}},
+		func(chunk string) error { return nil }, WithStreaming(true))
+
+	assert.EqualError(t, err, "stream error: overloaded_error: Overloaded")
+}
@@ -0,0 +1,117 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/SaiNageswarS/go-api-boot/logger"
+	"github.com/ollama/ollama/api"
+)
+
+// Embedder converts texts into embedding vectors for semantic search. Vectors of the same
+// embedder can be compared with cosine similarity.
+type Embedder interface {
+	// Embed returns one vector per text, in the order of texts.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// OllamaEmbedder computes embeddings with an Ollama embedding model such as nomic-embed-text.
+type OllamaEmbedder struct {
+	cli   embedAPI
+	model string
+}
+
+// embedAPI interface for Ollama embedding operations
+type embedAPI interface {
+	Embed(ctx context.Context, req *api.EmbedRequest) (*api.EmbedResponse, error)
+}
+
+// NewOllamaEmbedder creates an embedder for model on the server at OLLAMA_HOST.
+func NewOllamaEmbedder(model string) *OllamaEmbedder {
+	ollamaHost := os.Getenv("OLLAMA_HOST")
+	if ollamaHost == "" {
+		logger.Fatal("OLLAMA_HOST environment variable is not set")
+		return nil
+	}
+
+	ollamaClient, _ := api.ClientFromEnvironment()
+	return &OllamaEmbedder{cli: ollamaClient, model: model}
+}
+
+func (e *OllamaEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	resp, err := e.cli.Embed(ctx, &api.EmbedRequest{Model: e.model, Input: texts})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("ollama returned %d embeddings for %d texts", len(resp.Embeddings), len(texts))
+	}
+	return resp.Embeddings, nil
+}
+
+// HashEmbedder is a deterministic local Embedder hashing the words of a text into a fixed number
+// of dimensions. Texts sharing words are similar, without any notion of meaning, which makes it
+// suitable for tests and offline development but not for production retrieval.
+type HashEmbedder struct {
+	dimensions int
+}
+
+// NewHashEmbedder creates a hash embedder producing vectors of the given dimensions.
+func NewHashEmbedder(dimensions int) *HashEmbedder {
+	return &HashEmbedder{dimensions: dimensions}
+}
+
+func (e *HashEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if e.dimensions <= 0 {
+		return nil, errors.New("hash embedder needs at least one dimension")
+	}
+
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vector := make([]float32, e.dimensions)
+		words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		})
+		for _, word := range words {
+			h := fnv.New64a()
+			h.Write([]byte(word))
+			sum := h.Sum64()
+
+			// The top bit picks the sign so unrelated words tend to cancel out
+			sign := float32(1)
+			if sum>>63 == 1 {
+				sign = -1
+			}
+			vector[sum%uint64(e.dimensions)] += sign
+		}
+		vectors[i] = normalize(vector)
+	}
+	return vectors, nil
+}
+
+// normalize scales vector to unit length in place, leaving zero vectors unchanged.
+func normalize(vector []float32) []float32 {
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v) * float64(v)
+	}
+	if norm == 0 {
+		return vector
+	}
+
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range vector {
+		vector[i] *= scale
+	}
+	return vector
+}
@@ -0,0 +1,75 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEmbedAPI struct {
+	req  *api.EmbedRequest
+	resp *api.EmbedResponse
+	err  error
+}
+
+func (f *fakeEmbedAPI) Embed(ctx context.Context, req *api.EmbedRequest) (*api.EmbedResponse, error) {
+	f.req = req
+	return f.resp, f.err
+}
+
+func TestOllamaEmbedder(t *testing.T) {
+	cli := &fakeEmbedAPI{resp: &api.EmbedResponse{Embeddings: [][]float32{{0.1, 0.2}, {0.3, 0.4}}}}
+	embedder := &OllamaEmbedder{cli: cli, model: "nomic-embed-text"}
+
+	vectors, err := embedder.Embed(context.Background(), []string{"first", "second"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{0.1, 0.2}, {0.3, 0.4}}, vectors)
+	assert.Equal(t, "nomic-embed-text", cli.req.Model)
+	assert.Equal(t, []string{"first", "second"}, cli.req.Input)
+
+	_, err = embedder.Embed(context.Background(), []string{"only one"})
+	assert.ErrorContains(t, err, "2 embeddings for 1 texts")
+
+	cli.err = errors.New("connection refused")
+	_, err = embedder.Embed(context.Background(), []string{"first", "second"})
+	assert.Error(t, err)
+}
+
+func TestHashEmbedder(t *testing.T) {
+	embedder := NewHashEmbedder(256)
+
+	vectors, err := embedder.Embed(context.Background(), []string{
+		"The user lives in Berlin",
+		"the USER lives in berlin!",
+		"Favorite programming language is Go",
+		"",
+	})
+	require.NoError(t, err)
+	require.Len(t, vectors, 4)
+
+	assert.Len(t, vectors[0], 256)
+	assert.InDelta(t, 1, dot(vectors[0], vectors[0]), 1e-5, "Vectors are normalized")
+	assert.InDelta(t, 1, dot(vectors[0], vectors[1]), 1e-5, "Case and punctuation are ignored")
+	assert.Less(t, dot(vectors[0], vectors[2]), float32(0.5))
+	assert.Zero(t, dot(vectors[3], vectors[3]))
+
+	again, err := embedder.Embed(context.Background(), []string{"The user lives in Berlin"})
+	require.NoError(t, err)
+	assert.Equal(t, vectors[0], again[0], "Embeddings are deterministic")
+
+	_, err = NewHashEmbedder(0).Embed(context.Background(), []string{"text"})
+	assert.Error(t, err)
+}
+
+func dot(a, b []float32) float32 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return float32(math.Round(sum*1e6) / 1e6)
+}
@@ -0,0 +1,111 @@
+package llm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SaiNageswarS/go-api-boot/logger"
+	"github.com/ollama/ollama/api"
+	"go.uber.org/zap"
+)
+
+// FallbackClient tries its clients in order until one succeeds, e.g. falling back from a hosted
+// provider to a local Ollama model when the quota runs out.
+//
+// A call only moves on to the next client while nothing has been passed to the callbacks yet,
+// so a response is never duplicated or mixed from two models. Errors returned by the callbacks
+// and cancelled contexts are returned as is.
+type FallbackClient struct {
+	clients []LLMClient
+}
+
+func NewFallbackClient(primary LLMClient, fallbacks ...LLMClient) *FallbackClient {
+	return &FallbackClient{clients: append([]LLMClient{primary}, fallbacks...)}
+}
+
+// Capabilities returns the capabilities shared by all clients, since any of them may serve a call.
+func (c *FallbackClient) Capabilities() Capability {
+	capabilities := c.clients[0].Capabilities()
+	for _, client := range c.clients[1:] {
+		capabilities &= client.Capabilities()
+	}
+	return capabilities
+}
+
+// GetModel returns the model of the primary client. The usage reported to WithUsageCallback names
+// the model that served each call.
+func (c *FallbackClient) GetModel() string {
+	return c.clients[0].GetModel()
+}
+
+func (c *FallbackClient) GenerateInference(ctx context.Context, messages []Message, callback func(chunk string) error, opts ...LLMOption) error {
+	return c.try(ctx, func(client LLMClient, guard *outputGuard) error {
+		return client.GenerateInference(ctx, messages, guard.content(callback), opts...)
+	})
+}
+
+func (c *FallbackClient) GenerateInferenceWithTools(
+	ctx context.Context,
+	messages []Message,
+	contentCallback func(chunk string) error,
+	toolCallback func(toolCalls []api.ToolCall) error,
+	opts ...LLMOption,
+) error {
+	return c.try(ctx, func(client LLMClient, guard *outputGuard) error {
+		return client.GenerateInferenceWithTools(ctx, messages, guard.content(contentCallback), guard.toolCalls(toolCallback), opts...)
+	})
+}
+
+func (c *FallbackClient) try(ctx context.Context, call func(client LLMClient, guard *outputGuard) error) error {
+	var errs []error
+	for i, client := range c.clients {
+		guard := &outputGuard{}
+		err := call(client, guard)
+		if err == nil || guard.emitted || guard.callbackErr != nil || ctx.Err() != nil {
+			return err
+		}
+
+		errs = append(errs, err)
+		if i < len(c.clients)-1 {
+			logger.Error("LLM client failed, falling back",
+				zap.String("model", client.GetModel()),
+				zap.String("fallback", c.clients[i+1].GetModel()),
+				zap.Error(err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// outputGuard records whether a call produced output and whether a callback failed.
+type outputGuard struct {
+	emitted     bool
+	callbackErr error
+}
+
+func (g *outputGuard) content(callback func(chunk string) error) func(chunk string) error {
+	if callback == nil {
+		return nil
+	}
+	return func(chunk string) error {
+		g.emitted = true
+		if err := callback(chunk); err != nil {
+			g.callbackErr = err
+			return err
+		}
+		return nil
+	}
+}
+
+func (g *outputGuard) toolCalls(callback func(toolCalls []api.ToolCall) error) func(toolCalls []api.ToolCall) error {
+	if callback == nil {
+		return nil
+	}
+	return func(toolCalls []api.ToolCall) error {
+		g.emitted = true
+		if err := callback(toolCalls); err != nil {
+			g.callbackErr = err
+			return err
+		}
+		return nil
+	}
+}
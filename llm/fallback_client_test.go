@@ -0,0 +1,132 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLLMClient streams chunks and tool calls, then returns err.
+type fakeLLMClient struct {
+	model        string
+	capabilities Capability
+	chunks       []string
+	toolCalls    []api.ToolCall
+	err          error
+	calls        int
+}
+
+func (f *fakeLLMClient) GenerateInference(ctx context.Context, messages []Message, callback func(chunk string) error, opts ...LLMOption) error {
+	return f.GenerateInferenceWithTools(ctx, messages, callback, nil, opts...)
+}
+
+func (f *fakeLLMClient) GenerateInferenceWithTools(
+	ctx context.Context,
+	messages []Message,
+	contentCallback func(chunk string) error,
+	toolCallback func(toolCalls []api.ToolCall) error,
+	opts ...LLMOption,
+) error {
+	f.calls++
+	for _, chunk := range f.chunks {
+		if err := contentCallback(chunk); err != nil {
+			return err
+		}
+	}
+	if len(f.toolCalls) > 0 && toolCallback != nil {
+		if err := toolCallback(f.toolCalls); err != nil {
+			return err
+		}
+	}
+	return f.err
+}
+
+func (f *fakeLLMClient) Capabilities() Capability { return f.capabilities }
+
+func (f *fakeLLMClient) GetModel() string { return f.model }
+
+func collect(t *testing.T, client LLMClient) (string, error) {
+	t.Helper()
+	var result string
+	err := client.GenerateInference(context.Background(), []Message{{Role: "user", Content: "Hi"}}, func(chunk string) error {
+		result += chunk
+		return nil
+	})
+	return result, err
+}
+
+func TestFallbackClientFallsBackOnError(t *testing.T) {
+	primary := &fakeLLMClient{model: "groq", err: errors.New("API request failed with status 429")}
+	secondary := &fakeLLMClient{model: "ollama", chunks: []string{"from ", "ollama"}}
+
+	result, err := collect(t, NewFallbackClient(primary, secondary))
+
+	require.NoError(t, err)
+	assert.Equal(t, "from ollama", result)
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 1, secondary.calls)
+}
+
+func TestFallbackClientUsesPrimaryWhenItSucceeds(t *testing.T) {
+	primary := &fakeLLMClient{model: "groq", chunks: []string{"from groq"}}
+	secondary := &fakeLLMClient{model: "ollama", chunks: []string{"from ollama"}}
+
+	result, err := collect(t, NewFallbackClient(primary, secondary))
+
+	require.NoError(t, err)
+	assert.Equal(t, "from groq", result)
+	assert.Equal(t, 0, secondary.calls)
+}
+
+func TestFallbackClientAllFail(t *testing.T) {
+	primary := &fakeLLMClient{err: errors.New("quota exceeded")}
+	secondary := &fakeLLMClient{err: errors.New("connection refused")}
+
+	_, err := collect(t, NewFallbackClient(primary, secondary))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "quota exceeded")
+	assert.Contains(t, err.Error(), "connection refused")
+}
+
+func TestFallbackClientDoesNotFallBackAfterOutput(t *testing.T) {
+	primary := &fakeLLMClient{chunks: []string{"partial"}, err: errors.New("stream interrupted")}
+	secondary := &fakeLLMClient{chunks: []string{"complete"}}
+
+	result, err := collect(t, NewFallbackClient(primary, secondary))
+
+	assert.EqualError(t, err, "stream interrupted")
+	assert.Equal(t, "partial", result)
+	assert.Equal(t, 0, secondary.calls)
+}
+
+func TestFallbackClientToolCalls(t *testing.T) {
+	calls := []api.ToolCall{{Function: api.ToolCallFunction{Name: "search"}}}
+	primary := &fakeLLMClient{err: errors.New("overloaded")}
+	secondary := &fakeLLMClient{toolCalls: calls}
+
+	var received []api.ToolCall
+	err := NewFallbackClient(primary, secondary).GenerateInferenceWithTools(context.Background(), nil,
+		func(string) error { return nil },
+		func(toolCalls []api.ToolCall) error {
+			received = toolCalls
+			return nil
+		})
+
+	require.NoError(t, err)
+	assert.Equal(t, calls, received)
+}
+
+func TestFallbackClientCapabilitiesAndModel(t *testing.T) {
+	client := NewFallbackClient(
+		&fakeLLMClient{model: "groq", capabilities: NativeToolCalling},
+		&fakeLLMClient{model: "ollama"},
+	)
+
+	assert.Equal(t, Capability(0), client.Capabilities())
+	assert.Equal(t, "groq", client.GetModel())
+}
@@ -60,10 +60,10 @@ func TestGroqClientGenerateInference(t *testing.T) {
 		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
 
 		// Mock response
-		response := groqResponse{
-			Choices: []groqChoice{
+		response := openAIResponse{
+			Choices: []openAIChoice{
 				{
-					Message: groqMessage{
+					Message: openAIMessage{
 						Content: "Hello, this is a test response",
 					},
 				},
@@ -98,15 +98,15 @@ func TestGroqClientGenerateInferenceWithTools(t *testing.T) {
 	// Create a mock server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Mock response with tool calls
-		response := groqResponse{
-			Choices: []groqChoice{
+		response := openAIResponse{
+			Choices: []openAIChoice{
 				{
-					Message: groqMessage{
-						ToolCalls: []groqToolCall{
+					Message: openAIMessage{
+						ToolCalls: []openAIToolCall{
 							{
 								ID:   "call_123",
 								Type: "function",
-								Function: groqToolCallFunction{
+								Function: openAIToolCallFunction{
 									Name:      "calculator",
 									Arguments: `{"expression": "2+2"}`,
 								},
@@ -161,28 +161,10 @@ func TestGroqClientGenerateInferenceWithTools(t *testing.T) {
 	assert.Equal(t, "2+2", toolCalls[0].Function.Arguments["expression"])
 }
 
-func TestConvertToolsToGroqFormat(t *testing.T) {
-	tools := []api.Tool{
-		{
-			Function: api.ToolFunction{
-				Name:        "calculator",
-				Description: "Calculate mathematical expressions",
-			},
-		},
-	}
-
-	groqTools := convertToolsToGroqFormat(tools)
-
-	require.Len(t, groqTools, 1)
-	assert.Equal(t, "function", groqTools[0].Type)
-	assert.Equal(t, "calculator", groqTools[0].Function.Name)
-	assert.Equal(t, "Calculate mathematical expressions", groqTools[0].Function.Description)
-}
-
 func TestGroqClientWithSystemPrompt(t *testing.T) {
 	// Create a mock server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		var request groqRequest
+		var request openAIRequest
 		err := json.NewDecoder(r.Body).Decode(&request)
 		require.NoError(t, err)
 
@@ -194,10 +176,10 @@ func TestGroqClientWithSystemPrompt(t *testing.T) {
 		assert.Equal(t, "Hello", request.Messages[1].Content)
 
 		// Mock response
-		response := groqResponse{
-			Choices: []groqChoice{
+		response := openAIResponse{
+			Choices: []openAIChoice{
 				{
-					Message: groqMessage{
+					Message: openAIMessage{
 						Content: "Hello! How can I help you?",
 					},
 				},
@@ -0,0 +1,77 @@
+package llm
+
+import (
+	"net/http"
+)
+
+// ClientOption configures the HTTP-based clients: Anthropic, Groq and OpenAI-compatible servers.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	httpClient   *http.Client
+	headers      map[string]string
+	capabilities func(model string) Capability
+	retry        RetryPolicy
+	limiter      *RateLimiter
+
+	maxCompletionTokens *bool
+}
+
+func newClientOptions(opts []ClientOption) clientOptions {
+	o := clientOptions{
+		httpClient: &http.Client{},
+		headers:    map[string]string{},
+		retry:      DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithHeader adds a header sent with every request, e.g. an organization ID or a beta flag.
+func WithHeader(key, value string) ClientOption {
+	return func(o *clientOptions) { o.headers[key] = value }
+}
+
+// WithHTTPClient replaces the default HTTP client, e.g. to set timeouts or a proxy.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(o *clientOptions) { o.httpClient = httpClient }
+}
+
+// WithCapabilities sets the capabilities reported for every model.
+func WithCapabilities(capabilities Capability) ClientOption {
+	return func(o *clientOptions) {
+		o.capabilities = func(string) Capability { return capabilities }
+	}
+}
+
+// WithCapabilityDetector decides the capabilities from the model name.
+func WithCapabilityDetector(detect func(model string) Capability) ClientOption {
+	return func(o *clientOptions) { o.capabilities = detect }
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy. Use RetryPolicy{} to disable retries.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(o *clientOptions) { o.retry = policy }
+}
+
+// WithRateLimiter makes the client wait for the limiter before each request, including retries.
+// Share one limiter between clients to apply a common limit.
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(o *clientOptions) { o.limiter = limiter }
+}
+
+// WithMaxCompletionTokens decides whether OpenAI-compatible clients send the token limit as
+// max_completion_tokens, as OpenAI expects, or as max_tokens, as most other servers expect. By
+// default max_completion_tokens is only sent to api.openai.com.
+func WithMaxCompletionTokens(enabled bool) ClientOption {
+	return func(o *clientOptions) { o.maxCompletionTokens = &enabled }
+}
+
+// setHeaders sets the custom headers on req.
+func setHeaders(req *http.Request, headers map[string]string) {
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+}
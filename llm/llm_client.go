@@ -35,12 +35,15 @@ type LLMClient interface {
 }
 
 type LLMSettings struct {
-	model       string     // model name
-	temperature float64    // randomness (0.0 to 1.0)
-	maxTokens   int        // maximum tokens to generate
-	system      string     // system prompt
-	stream      bool       // whether to stream response
-	tools       []api.Tool // tools to use for tool calling
+	model       string                    // model name
+	temperature float64                   // randomness (0.0 to 1.0)
+	maxTokens   int                       // maximum tokens to generate
+	system      string                    // system prompt
+	stream      bool                      // whether to stream response
+	tools       []api.Tool                // tools to use for tool calling
+	toolSchemas map[string]map[string]any // full parameter schemas by tool name
+	onUsage     func(Usage)               // receives the token usage of the call
+	jsonSchema  map[string]any            // JSON schema the response must conform to
 }
 
 type LLMOption func(*LLMSettings)
@@ -66,8 +69,73 @@ func WithTools(tools []api.Tool) LLMOption {
 	return func(s *LLMSettings) { s.tools = tools }
 }
 
+// WithToolSchemas sets the complete JSON schemas of the tool parameters by tool name, including
+// bounds, defaults and nested objects that api.ToolProperty cannot carry. Providers accepting
+// arbitrary JSON schemas send them instead of the parameters of WithTools; Ollama ignores them.
+func WithToolSchemas(schemas map[string]map[string]any) LLMOption {
+	return func(s *LLMSettings) { s.toolSchemas = schemas }
+}
+
+// WithJSONSchema asks the provider to constrain the response to JSON matching schema, using its
+// JSON mode where available. Providers without one ignore it, so callers should still validate.
+func WithJSONSchema(schema map[string]any) LLMOption {
+	return func(s *LLMSettings) { s.jsonSchema = schema }
+}
+
+// WithUsageCallback receives the token usage reported by the provider once the call completes.
+func WithUsageCallback(fn func(Usage)) LLMOption {
+	return func(s *LLMSettings) { s.onUsage = fn }
+}
+
+// ReportUsage passes the token usage of a call to the callback set with WithUsageCallback, if any.
+// A usage without a Model is attributed to the model of the settings.
+func (s *LLMSettings) ReportUsage(usage Usage) {
+	if s.onUsage != nil {
+		if usage.Model == "" {
+			usage.Model = s.model
+		}
+		s.onUsage(usage)
+	}
+}
+
+// Usage is the number of tokens consumed by an inference call.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	// Model is the model that served the call, which differs from the GetModel of clients such as
+	// FallbackClient and RouterClient when another of their clients served it.
+	Model string `json:"model,omitempty"`
+}
+
+func (u Usage) TotalTokens() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// Add returns the sum of two usages. The sum only keeps a Model both usages share.
+func (u Usage) Add(other Usage) Usage {
+	sum := Usage{
+		PromptTokens:     u.PromptTokens + other.PromptTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+	}
+	if u.Model == other.Model {
+		sum.Model = u.Model
+	}
+	return sum
+}
+
 type Message struct {
-	Role         string `bson:"role" json:"role"`        // "user", "assistant", "system"
-	Content      string `bson:"content" json:"content"`  // the message content
-	IsToolResult bool   `bson:"is_tool_result" json:"-"` // true if this message is a result of a tool call
+	Role    string `bson:"role" json:"role"`       // "user", "assistant", "system", "tool"
+	Content string `bson:"content" json:"content"` // the message content
+
+	// Deprecated: tool results are "tool" messages. Set on tool results saved as "user" messages
+	// by earlier versions, which are still sent to providers as user messages.
+	IsToolResult bool `bson:"is_tool_result,omitempty" json:"-"`
+
+	// ToolCalls are the tool calls requested by an "assistant" message.
+	ToolCalls []api.ToolCall `bson:"tool_calls,omitempty" json:"tool_calls,omitempty"`
+	// ToolCallID links a "tool" message to the call it answers. When empty, providers that need
+	// IDs pair tool messages with the calls of the preceding assistant message by position.
+	ToolCallID string `bson:"tool_call_id,omitempty" json:"tool_call_id,omitempty"`
+	// ToolName is the name of the tool whose result a "tool" message holds.
+	ToolName string `bson:"tool_name,omitempty" json:"tool_name,omitempty"`
 }
@@ -2,11 +2,13 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"strings"
 
 	"github.com/SaiNageswarS/go-api-boot/logger"
 	"github.com/ollama/ollama/api"
+	"go.uber.org/zap"
 )
 
 type OllamaLLMClient struct {
@@ -52,13 +54,7 @@ func (c *OllamaLLMClient) GenerateInference(ctx context.Context, messages []Mess
 	}
 
 	// Convert messages to Ollama format
-	ollamaMessages := make([]api.Message, len(messages))
-	for i, msg := range messages {
-		ollamaMessages[i] = api.Message{
-			Role:    msg.Role,
-			Content: msg.Content,
-		}
-	}
+	ollamaMessages := convertMessagesToOllamaFormat(messages)
 
 	req := &api.ChatRequest{
 		Model:    settings.model,
@@ -68,6 +64,7 @@ func (c *OllamaLLMClient) GenerateInference(ctx context.Context, messages []Mess
 			"num_predict": settings.maxTokens,
 		},
 		Stream: &settings.stream,
+		Format: jsonSchemaFormat(settings.jsonSchema),
 	}
 
 	// Add system prompt if provided
@@ -80,6 +77,10 @@ func (c *OllamaLLMClient) GenerateInference(ctx context.Context, messages []Mess
 	}
 
 	responseFunc := func(resp api.ChatResponse) error {
+		if resp.Done {
+			settings.ReportUsage(Usage{PromptTokens: resp.PromptEvalCount, CompletionTokens: resp.EvalCount})
+		}
+
 		if resp.Message.Content != "" {
 			// Call the user-provided callback with each chunk
 			return callback(resp.Message.Content)
@@ -111,13 +112,7 @@ func (c *OllamaLLMClient) GenerateInferenceWithTools(
 	}
 
 	// Convert messages to Ollama format
-	ollamaMessages := make([]api.Message, len(messages))
-	for i, msg := range messages {
-		ollamaMessages[i] = api.Message{
-			Role:    msg.Role,
-			Content: msg.Content,
-		}
-	}
+	ollamaMessages := convertMessagesToOllamaFormat(messages)
 
 	req := &api.ChatRequest{
 		Model:    settings.model,
@@ -128,6 +123,7 @@ func (c *OllamaLLMClient) GenerateInferenceWithTools(
 		},
 		Stream: &settings.stream,
 		Tools:  settings.tools,
+		Format: jsonSchemaFormat(settings.jsonSchema),
 	}
 
 	// Add system prompt if provided
@@ -140,6 +136,10 @@ func (c *OllamaLLMClient) GenerateInferenceWithTools(
 	}
 
 	responseFunc := func(resp api.ChatResponse) error {
+		if resp.Done {
+			settings.ReportUsage(Usage{PromptTokens: resp.PromptEvalCount, CompletionTokens: resp.EvalCount})
+		}
+
 		if resp.Message.Content != "" {
 			// Call the content callback with each chunk
 			return contentCallback(resp.Message.Content)
@@ -160,3 +160,50 @@ func (c *OllamaLLMClient) GenerateInferenceWithTools(
 type chatAPI interface {
 	Chat(ctx context.Context, req *api.ChatRequest, fn api.ChatResponseFunc) error
 }
+
+// convertMessagesToOllamaFormat converts messages to Ollama messages, which carry assistant tool
+// calls and "tool" results natively. Tool messages without a ToolName get the name of the call
+// they answer, matching them with the calls of the preceding assistant message in order.
+func convertMessagesToOllamaFormat(messages []Message) []api.Message {
+	result := make([]api.Message, len(messages))
+	var pendingNames []string
+
+	for i, msg := range messages {
+		result[i] = api.Message{
+			Role:      msg.Role,
+			Content:   msg.Content,
+			ToolCalls: msg.ToolCalls,
+			ToolName:  msg.ToolName,
+		}
+
+		switch {
+		case msg.Role == "tool":
+			if len(pendingNames) > 0 {
+				if result[i].ToolName == "" {
+					result[i].ToolName = pendingNames[0]
+				}
+				pendingNames = pendingNames[1:]
+			}
+
+		case len(msg.ToolCalls) > 0:
+			pendingNames = pendingNames[:0]
+			for _, call := range msg.ToolCalls {
+				pendingNames = append(pendingNames, call.Function.Name)
+			}
+		}
+	}
+	return result
+}
+
+// jsonSchemaFormat returns the schema as Ollama's structured output format, or nil without one.
+func jsonSchemaFormat(schema map[string]any) json.RawMessage {
+	if schema == nil {
+		return nil
+	}
+	format, err := json.Marshal(schema)
+	if err != nil {
+		logger.Error("Ignoring JSON schema that cannot be marshaled", zap.Error(err))
+		return nil
+	}
+	return format
+}
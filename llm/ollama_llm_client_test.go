@@ -6,6 +6,7 @@ import (
 
 	"github.com/ollama/ollama/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestProvideOllamaClient_MissingAPIKey(t *testing.T) {
@@ -66,6 +67,54 @@ func TestOllamaLLMClient_Settings(t *testing.T) {
 	assert.Equal(t, "system", client.cli.(*mockChatAPI).reqReceived.Messages[0].Role)
 }
 
+func TestOllamaLLMClient_JSONSchema(t *testing.T) {
+	client := &OllamaLLMClient{cli: &mockChatAPI{mockResponse: `{"city":"Paris"}`}, model: "llama3.2"}
+
+	schema := map[string]any{"type": "object", "properties": map[string]any{"city": map[string]any{"type": "string"}}}
+	err := client.GenerateInference(t.Context(), []Message{{Role: "user", Content: "Capital of France?"}},
+		func(chunk string) error { return nil }, WithJSONSchema(schema))
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"object","properties":{"city":{"type":"string"}}}`, string(client.cli.(*mockChatAPI).reqReceived.Format))
+
+	// Without a schema the response format is left to the model
+	err = client.GenerateInference(t.Context(), []Message{{Role: "user", Content: "Hello"}}, func(chunk string) error { return nil })
+	assert.NoError(t, err)
+	assert.Nil(t, client.cli.(*mockChatAPI).reqReceived.Format)
+}
+
+func TestConvertMessagesToOllamaFormat(t *testing.T) {
+	calls := []api.ToolCall{
+		{Function: api.ToolCallFunction{Name: "search", Arguments: api.ToolCallFunctionArguments{"query": "go"}}},
+		{Function: api.ToolCallFunction{Name: "calculator", Arguments: api.ToolCallFunctionArguments{"expression": "2+2"}}},
+	}
+
+	converted := convertMessagesToOllamaFormat([]Message{
+		{Role: "user", Content: "Search Go and add 2+2"},
+		{Role: "assistant", ToolCalls: calls},
+		{Role: "tool", Content: "Go is a language"},
+		{Role: "tool", Content: "4", ToolName: "calculator"},
+	})
+
+	require.Len(t, converted, 4)
+	assert.Equal(t, calls, converted[1].ToolCalls)
+	assert.Equal(t, api.Message{Role: "tool", Content: "Go is a language", ToolName: "search"}, converted[2])
+	assert.Equal(t, api.Message{Role: "tool", Content: "4", ToolName: "calculator"}, converted[3])
+}
+
+func TestOllamaLLMClient_Usage(t *testing.T) {
+	client := &OllamaLLMClient{cli: &mockChatAPI{mockResponse: "Hi"}}
+
+	var usage Usage
+	err := client.GenerateInference(t.Context(), []Message{{Role: "user", Content: "Hello"}},
+		func(chunk string) error { return nil },
+		WithUsageCallback(func(u Usage) { usage = u }))
+
+	assert.NoError(t, err)
+	assert.Equal(t, Usage{PromptTokens: 12, CompletionTokens: 5}, usage)
+	assert.Equal(t, 17, usage.TotalTokens())
+}
+
 type mockChatAPI struct {
 	mockResponse string
 	reqReceived  *api.ChatRequest
@@ -78,6 +127,8 @@ func (m *mockChatAPI) Chat(ctx context.Context, req *api.ChatRequest, callback a
 		Message: api.Message{
 			Content: m.mockResponse,
 		},
+		Done:    true,
+		Metrics: api.Metrics{PromptEvalCount: 12, EvalCount: 5},
 	}
 	return callback(response)
 }
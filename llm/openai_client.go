@@ -0,0 +1,465 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ollama/ollama/api"
+)
+
+// OpenAICompatibleClient talks to any server implementing the OpenAI chat completions API,
+// such as OpenAI, Groq, vLLM, LM Studio, the llama.cpp server or Together.
+type OpenAICompatibleClient struct {
+	apiKey       string
+	httpClient   *http.Client
+	url          string
+	model        string
+	headers      map[string]string
+	capabilities func(model string) Capability
+	retry        RetryPolicy
+	limiter      *RateLimiter
+
+	// maxCompletionTokens sends the token limit as max_completion_tokens instead of max_tokens
+	maxCompletionTokens bool
+}
+
+// NewOpenAICompatibleClient creates a client for the chat completions endpoint under baseURL,
+// e.g. "https://api.openai.com/v1" or "http://localhost:8000/v1". The apiKey may be empty for
+// local servers. Native tool calling is assumed unless overridden with WithCapabilities.
+func NewOpenAICompatibleClient(baseURL, apiKey, model string, opts ...ClientOption) *OpenAICompatibleClient {
+	url := strings.TrimSuffix(baseURL, "/")
+	if !strings.HasSuffix(url, "/chat/completions") {
+		url += "/chat/completions"
+	}
+
+	o := newClientOptions(opts)
+	if o.capabilities == nil {
+		o.capabilities = func(string) Capability { return NativeToolCalling }
+	}
+	maxCompletionTokens := strings.Contains(url, "://api.openai.com/")
+	if o.maxCompletionTokens != nil {
+		maxCompletionTokens = *o.maxCompletionTokens
+	}
+
+	return &OpenAICompatibleClient{
+		apiKey:       apiKey,
+		httpClient:   o.httpClient,
+		url:          url,
+		model:        model,
+		headers:      o.headers,
+		capabilities: o.capabilities,
+		retry:        o.retry,
+		limiter:      o.limiter,
+
+		maxCompletionTokens: maxCompletionTokens,
+	}
+}
+
+func (c *OpenAICompatibleClient) Capabilities() Capability {
+	return c.capabilities(c.model)
+}
+
+func (c *OpenAICompatibleClient) GetModel() string {
+	return c.model
+}
+
+func (c *OpenAICompatibleClient) GenerateInference(ctx context.Context, messages []Message, callback func(chunk string) error, opts ...LLMOption) error {
+	settings := c.settings(opts)
+	return c.makeRequest(ctx, settings, c.buildRequest(settings, messages), callback, nil)
+}
+
+func (c *OpenAICompatibleClient) GenerateInferenceWithTools(
+	ctx context.Context,
+	messages []Message,
+	contentCallback func(chunk string) error,
+	toolCallback func(toolCalls []api.ToolCall) error,
+	opts ...LLMOption,
+) error {
+	settings := c.settings(opts)
+
+	request := c.buildRequest(settings, messages)
+	request.Tools = convertToolsToOpenAIFormat(settings.tools, settings.toolSchemas)
+	if len(request.Tools) > 0 {
+		request.ToolChoice = "auto"
+	}
+
+	return c.makeRequest(ctx, settings, request, contentCallback, toolCallback)
+}
+
+func (c *OpenAICompatibleClient) settings(opts []LLMOption) LLMSettings {
+	// Default settings
+	settings := LLMSettings{
+		model:       c.model,
+		temperature: 0.7,
+		maxTokens:   4096,
+		stream:      false,
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(&settings)
+	}
+	return settings
+}
+
+func (c *OpenAICompatibleClient) buildRequest(settings LLMSettings, messages []Message) openAIRequest {
+	request := openAIRequest{
+		Model:       settings.model,
+		Messages:    convertMessagesToOpenAIFormat(messages),
+		Temperature: settings.temperature,
+		Stream:      settings.stream,
+	}
+	if c.maxCompletionTokens {
+		request.MaxCompletionTokens = settings.maxTokens
+	} else {
+		request.MaxTokens = settings.maxTokens
+	}
+	if settings.stream {
+		// Without this the usage of streamed responses is not reported
+		request.StreamOptions = &openAIStreamOptions{IncludeUsage: true}
+	}
+	if settings.jsonSchema != nil {
+		request.ResponseFormat = &openAIResponseFormat{
+			Type:       "json_schema",
+			JSONSchema: &openAIJSONSchema{Name: "response", Schema: settings.jsonSchema},
+		}
+	}
+
+	// Add system prompt if provided (the system message goes in the messages array)
+	if settings.system != "" {
+		systemMsg := openAIMessage{
+			Role:    "system",
+			Content: settings.system,
+		}
+		request.Messages = append([]openAIMessage{systemMsg}, request.Messages...)
+	}
+	return request
+}
+
+func (c *OpenAICompatibleClient) makeRequest(
+	ctx context.Context,
+	settings LLMSettings,
+	request openAIRequest,
+	contentCallback func(chunk string) error,
+	toolCallback func(toolCalls []api.ToolCall) error,
+) error {
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("error marshaling request: %w", err)
+	}
+
+	resp, err := doWithRetry(ctx, c.httpClient, c.retry, c.limiter, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", c.url, bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		if c.apiKey != "" {
+			req.Header.Set("Authorization", "Bearer "+c.apiKey)
+		}
+		if request.Stream {
+			req.Header.Set("Accept", "text/event-stream")
+		}
+		setHeaders(req, c.headers)
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var message openAIMessage
+	if request.Stream {
+		// Content is passed to the callback as it arrives; tool calls are complete only at the end
+		var usage Usage
+		if message, usage, err = readOpenAIStream(resp.Body, contentCallback); err != nil {
+			return err
+		}
+		settings.ReportUsage(usage)
+	} else {
+		var response openAIResponse
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			return fmt.Errorf("error unmarshaling response: %w", err)
+		}
+
+		if len(response.Choices) == 0 {
+			return fmt.Errorf("no choices in response")
+		}
+		message = response.Choices[0].Message
+		settings.ReportUsage(response.Usage.toUsage())
+	}
+
+	// Handle tool calls
+	if len(message.ToolCalls) > 0 && toolCallback != nil {
+		// Convert OpenAI tool calls to Ollama format for compatibility
+		ollamaToolCalls := make([]api.ToolCall, len(message.ToolCalls))
+		for i, tc := range message.ToolCalls {
+			// Parse the JSON arguments string into a map; some servers send "" for no arguments
+			args := map[string]any{}
+			if strings.TrimSpace(tc.Function.Arguments) != "" {
+				if err := json.Unmarshal([]byte(tc.Function.Arguments), &args); err != nil {
+					return fmt.Errorf("error parsing tool call arguments: %w", err)
+				}
+			}
+
+			ollamaToolCalls[i] = api.ToolCall{
+				Function: api.ToolCallFunction{
+					Name:      tc.Function.Name,
+					Arguments: args,
+				},
+			}
+		}
+		return toolCallback(ollamaToolCalls)
+	}
+
+	// Handle regular content
+	if !request.Stream && message.Content != "" && contentCallback != nil {
+		return contentCallback(message.Content)
+	}
+
+	return nil
+}
+
+// readOpenAIStream assembles the message and usage of a streamed chat completion. Content deltas are
+// passed to contentCallback as they arrive and tool call arguments are concatenated by call index.
+func readOpenAIStream(body io.Reader, contentCallback func(chunk string) error) (openAIMessage, Usage, error) {
+	var message openAIMessage
+	var usage Usage
+	var content strings.Builder
+
+	err := readSSE(body, func(_, data string) error {
+		if data == "[DONE]" {
+			return io.EOF
+		}
+
+		var chunk openAIStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("error unmarshaling stream chunk: %w", err)
+		}
+		if chunk.Error != nil {
+			return fmt.Errorf("stream error: %s", chunk.Error.Message)
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage.toUsage()
+		} else if chunk.XGroq != nil && chunk.XGroq.Usage != nil {
+			usage = chunk.XGroq.Usage.toUsage()
+		}
+		if len(chunk.Choices) == 0 {
+			return nil // e.g. the final usage chunk
+		}
+
+		delta := chunk.Choices[0].Delta
+		if delta.Role != "" {
+			message.Role = delta.Role
+		}
+
+		for _, tc := range delta.ToolCalls {
+			for len(message.ToolCalls) <= tc.Index {
+				message.ToolCalls = append(message.ToolCalls, openAIToolCall{})
+			}
+			call := &message.ToolCalls[tc.Index]
+			if tc.ID != "" {
+				call.ID = tc.ID
+			}
+			if tc.Type != "" {
+				call.Type = tc.Type
+			}
+			call.Function.Name += tc.Function.Name
+			call.Function.Arguments += tc.Function.Arguments
+		}
+
+		if delta.Content != "" {
+			content.WriteString(delta.Content)
+			if contentCallback != nil {
+				return contentCallback(delta.Content)
+			}
+		}
+		return nil
+	})
+
+	message.Content = content.String()
+	return message, usage, err
+}
+
+// convertMessagesToOpenAIFormat converts messages to the chat completions format. Assistant tool
+// calls get positional IDs, which "tool" messages without a ToolCallID are matched against in order.
+func convertMessagesToOpenAIFormat(messages []Message) []openAIMessage {
+	result := make([]openAIMessage, 0, len(messages))
+	var pendingIDs []string
+
+	for i, msg := range messages {
+		converted := openAIMessage{Role: msg.Role, Content: msg.Content, ToolCallID: msg.ToolCallID}
+
+		switch {
+		case msg.Role == "tool":
+			if converted.ToolCallID == "" && len(pendingIDs) > 0 {
+				converted.ToolCallID, pendingIDs = pendingIDs[0], pendingIDs[1:]
+			}
+			if converted.ToolCallID == "" {
+				// A result without a matching call is rejected as a tool message
+				converted.Role = "user"
+			}
+
+		case len(msg.ToolCalls) > 0:
+			pendingIDs = pendingIDs[:0]
+			for j, call := range msg.ToolCalls {
+				id := fmt.Sprintf("call_%d_%d", i, j)
+				pendingIDs = append(pendingIDs, id)
+
+				args, err := json.Marshal(call.Function.Arguments)
+				if err != nil || call.Function.Arguments == nil {
+					args = []byte("{}")
+				}
+				converted.ToolCalls = append(converted.ToolCalls, openAIToolCall{
+					ID:       id,
+					Type:     "function",
+					Function: openAIToolCallFunction{Name: call.Function.Name, Arguments: string(args)},
+				})
+			}
+		}
+
+		result = append(result, converted)
+	}
+	return result
+}
+
+// convertToolsToOpenAIFormat converts Ollama tools to OpenAI format, sending the schema of a tool
+// in schemas as its parameters when there is one
+func convertToolsToOpenAIFormat(tools []api.Tool, schemas map[string]map[string]any) []openAITool {
+	if len(tools) == 0 {
+		return nil
+	}
+
+	openAITools := make([]openAITool, len(tools))
+	for i, tool := range tools {
+		var params interface{} = tool.Function.Parameters
+		if schema, ok := schemas[tool.Function.Name]; ok {
+			params = schema
+		}
+
+		openAITools[i] = openAITool{
+			Type: "function",
+			Function: openAIFunction{
+				Name:        tool.Function.Name,
+				Description: tool.Function.Description,
+				Parameters:  params,
+			},
+		}
+	}
+	return openAITools
+}
+
+// OpenAI chat completions API types
+type openAIRequest struct {
+	Model               string                `json:"model"`
+	Messages            []openAIMessage       `json:"messages"`
+	Temperature         float64               `json:"temperature"` // always sent, 0 is a valid value
+	MaxTokens           int                   `json:"max_tokens,omitempty"`
+	MaxCompletionTokens int                   `json:"max_completion_tokens,omitempty"`
+	Stream              bool                  `json:"stream,omitempty"`
+	StreamOptions       *openAIStreamOptions  `json:"stream_options,omitempty"`
+	Tools               []openAITool          `json:"tools,omitempty"`
+	ToolChoice          string                `json:"tool_choice,omitempty"`
+	ResponseFormat      *openAIResponseFormat `json:"response_format,omitempty"`
+}
+
+type openAIResponseFormat struct {
+	Type       string            `json:"type"`
+	JSONSchema *openAIJSONSchema `json:"json_schema,omitempty"`
+}
+
+type openAIJSONSchema struct {
+	Name   string         `json:"name"`
+	Schema map[string]any `json:"schema"`
+}
+
+type openAITool struct {
+	Type     string         `json:"type"`
+	Function openAIFunction `json:"function"`
+}
+
+type openAIFunction struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Parameters  interface{} `json:"parameters"`
+}
+
+type openAIResponse struct {
+	ID      string         `json:"id"`
+	Object  string         `json:"object"`
+	Created int64          `json:"created"`
+	Model   string         `json:"model"`
+	Choices []openAIChoice `json:"choices"`
+	Usage   openAIUsage    `json:"usage"`
+}
+
+type openAIChoice struct {
+	Index        int           `json:"index"`
+	Message      openAIMessage `json:"message"`
+	FinishReason string        `json:"finish_reason"`
+}
+
+type openAIMessage struct {
+	Role       string           `json:"role"`
+	Content    string           `json:"content"`
+	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+type openAIToolCall struct {
+	ID       string                 `json:"id"`
+	Type     string                 `json:"type"`
+	Function openAIToolCallFunction `json:"function"`
+}
+
+type openAIToolCallFunction struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+type openAIStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Role      string                `json:"role"`
+			Content   string                `json:"content"`
+			ToolCalls []openAIToolCallDelta `json:"tool_calls"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *openAIUsage `json:"usage,omitempty"`
+	// Groq reports the usage of streamed responses here
+	XGroq *struct {
+		Usage *openAIUsage `json:"usage,omitempty"`
+	} `json:"x_groq,omitempty"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// openAIToolCallDelta is a fragment of a streamed tool call; Index identifies the call it belongs to.
+type openAIToolCallDelta struct {
+	Index    int                    `json:"index"`
+	ID       string                 `json:"id"`
+	Type     string                 `json:"type"`
+	Function openAIToolCallFunction `json:"function"`
+}
+
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+func (u openAIUsage) toUsage() Usage {
+	return Usage{PromptTokens: u.PromptTokens, CompletionTokens: u.CompletionTokens}
+}
@@ -0,0 +1,281 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOpenAICompatibleClient(t *testing.T) {
+	client := NewOpenAICompatibleClient("http://localhost:8000/v1/", "", "qwen2.5")
+
+	assert.Equal(t, "http://localhost:8000/v1/chat/completions", client.url)
+	assert.Equal(t, "qwen2.5", client.GetModel())
+	assert.Equal(t, NativeToolCalling, client.Capabilities())
+
+	client = NewOpenAICompatibleClient("https://example.com/v1/chat/completions", "key", "model")
+	assert.Equal(t, "https://example.com/v1/chat/completions", client.url)
+}
+
+func TestOpenAICompatibleClientCapabilityOptions(t *testing.T) {
+	client := NewOpenAICompatibleClient("http://localhost:8080/v1", "", "llama", WithCapabilities(0))
+	assert.Equal(t, Capability(0), client.Capabilities())
+
+	client = NewOpenAICompatibleClient("http://localhost:8080/v1", "", "gpt-4o", WithCapabilityDetector(func(model string) Capability {
+		if model == "gpt-4o" {
+			return NativeToolCalling
+		}
+		return 0
+	}))
+	assert.Equal(t, NativeToolCalling, client.Capabilities())
+}
+
+func TestOpenAICompatibleClientHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Empty(t, r.Header.Get("Authorization")) // no API key for local servers
+		assert.Equal(t, "org-123", r.Header.Get("OpenAI-Organization"))
+
+		_, _ = w.Write([]byte(`{
+			"choices": [{"message": {"role": "assistant", "content": "Hi"}}],
+			"usage": {"prompt_tokens": 8, "completion_tokens": 1, "total_tokens": 9}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenAICompatibleClient(server.URL+"/v1", "", "local-model", WithHeader("OpenAI-Organization", "org-123"))
+
+	var result string
+	var usage Usage
+	err := client.GenerateInference(context.Background(), []Message{{Role: "user", Content: "Hello"}}, func(chunk string) error {
+		result += chunk
+		return nil
+	}, WithUsageCallback(func(u Usage) { usage = u }))
+
+	require.NoError(t, err)
+	assert.Equal(t, Usage{PromptTokens: 8, CompletionTokens: 1, Model: "local-model"}, usage)
+	assert.Equal(t, "Hi", result)
+}
+
+func TestOpenAICompatibleClientEmptyToolArguments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"choices": [{"message": {"role": "assistant", "tool_calls": [
+				{"id": "call_1", "type": "function", "function": {"name": "current_time", "arguments": ""}}
+			]}}]
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenAICompatibleClient(server.URL, "", "local-model")
+
+	var toolCalls []api.ToolCall
+	err := client.GenerateInferenceWithTools(context.Background(), []Message{{Role: "user", Content: "What time is it?"}},
+		func(chunk string) error { return nil },
+		func(calls []api.ToolCall) error {
+			toolCalls = calls
+			return nil
+		})
+
+	require.NoError(t, err)
+	require.Len(t, toolCalls, 1)
+	assert.Equal(t, "current_time", toolCalls[0].Function.Name)
+	assert.Empty(t, toolCalls[0].Function.Arguments)
+}
+
+func TestOpenAICompatibleClientJSONSchema(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, map[string]any{
+			"type": "json_schema",
+			"json_schema": map[string]any{
+				"name":   "response",
+				"schema": map[string]any{"type": "object", "required": []any{"city"}},
+			},
+		}, request["response_format"])
+
+		_, _ = w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "{\"city\":\"Paris\"}"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenAICompatibleClient(server.URL, "", "local-model")
+
+	var result string
+	err := client.GenerateInference(context.Background(), []Message{{Role: "user", Content: "Capital of France?"}}, func(chunk string) error {
+		result += chunk
+		return nil
+	}, WithJSONSchema(map[string]any{"type": "object", "required": []string{"city"}}))
+
+	require.NoError(t, err)
+	assert.Equal(t, `{"city":"Paris"}`, result)
+}
+
+func TestOpenAICompatibleClientRequestLimits(t *testing.T) {
+	var request map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		_, _ = w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "Hi"}}]}`))
+	}))
+	defer server.Close()
+
+	generate := func(client *OpenAICompatibleClient) {
+		err := client.GenerateInference(context.Background(), []Message{{Role: "user", Content: "Hello"}},
+			func(string) error { return nil }, WithTemperature(0), WithMaxTokens(100))
+		require.NoError(t, err)
+	}
+
+	// Local servers such as llama.cpp and LM Studio expect max_tokens
+	generate(NewOpenAICompatibleClient(server.URL+"/v1", "", "local-model"))
+	assert.Contains(t, request, "temperature", "A temperature of 0 must be sent")
+	assert.Equal(t, 0.0, request["temperature"])
+	assert.Equal(t, 100.0, request["max_tokens"])
+	assert.NotContains(t, request, "max_completion_tokens")
+
+	generate(NewOpenAICompatibleClient(server.URL+"/v1", "", "gpt-4o", WithMaxCompletionTokens(true)))
+	assert.Equal(t, 100.0, request["max_completion_tokens"])
+	assert.NotContains(t, request, "max_tokens")
+
+	assert.True(t, NewOpenAICompatibleClient("https://api.openai.com/v1", "key", "gpt-4o").maxCompletionTokens)
+}
+
+func TestConvertMessagesToOpenAIFormat(t *testing.T) {
+	messages := []Message{
+		{Role: "user", Content: "What is 2+2?"},
+		{
+			Role: "assistant",
+			ToolCalls: []api.ToolCall{
+				{Function: api.ToolCallFunction{Name: "calculator", Arguments: api.ToolCallFunctionArguments{"expression": "2+2"}}},
+			},
+		},
+		{Role: "tool", Content: "4"},
+		{Role: "user", Content: "Thanks"},
+	}
+
+	converted := convertMessagesToOpenAIFormat(messages)
+
+	require.Len(t, converted, 4)
+	require.Len(t, converted[1].ToolCalls, 1)
+	call := converted[1].ToolCalls[0]
+	assert.Equal(t, "function", call.Type)
+	assert.Equal(t, "calculator", call.Function.Name)
+	assert.JSONEq(t, `{"expression": "2+2"}`, call.Function.Arguments)
+	assert.NotEmpty(t, call.ID)
+	assert.Equal(t, call.ID, converted[2].ToolCallID)
+	assert.Equal(t, openAIMessage{Role: "user", Content: "Thanks"}, converted[3])
+}
+
+func TestConvertMessagesToOpenAIFormat_UnmatchedToolResult(t *testing.T) {
+	converted := convertMessagesToOpenAIFormat([]Message{
+		{Role: "user", Content: "What is 2+2?"},
+		{Role: "tool", Content: "4"},
+	})
+
+	require.Len(t, converted, 2)
+	assert.Equal(t, openAIMessage{Role: "user", Content: "4"}, converted[1])
+}
+
+func TestConvertToolsToOpenAIFormat(t *testing.T) {
+	tools := []api.Tool{
+		{
+			Function: api.ToolFunction{
+				Name:        "calculator",
+				Description: "Calculate mathematical expressions",
+			},
+		},
+	}
+
+	openAITools := convertToolsToOpenAIFormat(tools, nil)
+
+	require.Len(t, openAITools, 1)
+	assert.Equal(t, "function", openAITools[0].Type)
+	assert.Equal(t, "calculator", openAITools[0].Function.Name)
+	assert.Equal(t, "Calculate mathematical expressions", openAITools[0].Function.Description)
+}
+
+func TestConvertToolsToOpenAIFormatWithSchema(t *testing.T) {
+	tools := []api.Tool{{Function: api.ToolFunction{Name: "calculator"}}, {Function: api.ToolFunction{Name: "clock"}}}
+	schema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"precision": map[string]any{"type": "integer", "minimum": 0, "maximum": 10}},
+	}
+
+	openAITools := convertToolsToOpenAIFormat(tools, map[string]map[string]any{"calculator": schema})
+
+	require.Len(t, openAITools, 2)
+	assert.Equal(t, schema, openAITools[0].Function.Parameters)
+	assert.Equal(t, tools[1].Function.Parameters, openAITools[1].Function.Parameters)
+}
+
+func TestOpenAICompatibleClientStreaming(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request openAIRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.True(t, request.Stream)
+		require.NotNil(t, request.StreamOptions)
+		assert.True(t, request.StreamOptions.IncludeUsage)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(
+			`data: {"choices": [{"delta": {"role": "assistant", "content": ""}}]}` + "\n\n" +
+				`data: {"choices": [{"delta": {"content": "Hello"}}]}` + "\n\n" +
+				`data: {"choices": [{"delta": {"content": " there"}}]}` + "\n\n" +
+				`data: {"choices": [{"delta": {}, "finish_reason": "stop"}]}` + "\n\n" +
+				`data: {"choices": [], "usage": {"prompt_tokens": 9, "completion_tokens": 2, "total_tokens": 11}}` + "\n\n" +
+				"data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	client := NewOpenAICompatibleClient(server.URL, "test-key", "gpt-4o")
+
+	var chunks []string
+	var usage Usage
+	err := client.GenerateInference(context.Background(), []Message{{Role: "user", Content: "Hi"}}, func(chunk string) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}, WithStreaming(true), WithUsageCallback(func(u Usage) { usage = u }))
+
+	require.NoError(t, err)
+	assert.Equal(t, Usage{PromptTokens: 9, CompletionTokens: 2, Model: "gpt-4o"}, usage)
+	assert.Equal(t, []string{"Hello", " there"}, chunks)
+}
+
+func TestOpenAICompatibleClientStreamingToolCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(
+			`data: {"choices": [{"delta": {"role": "assistant", "tool_calls": [{"index": 0, "id": "call_1", "type": "function", "function": {"name": "calculator", "arguments": ""}}]}}]}` + "\n\n" +
+				`data: {"choices": [{"delta": {"tool_calls": [{"index": 0, "function": {"arguments": "{\"expression\":"}}]}}]}` + "\n\n" +
+				`data: {"choices": [{"delta": {"tool_calls": [{"index": 1, "id": "call_2", "type": "function", "function": {"name": "search", "arguments": "{\"query\": \"go\"}"}}]}}]}` + "\n\n" +
+				`data: {"choices": [{"delta": {"tool_calls": [{"index": 0, "function": {"arguments": " \"2+2\"}"}}]}}]}` + "\n\n" +
+				`data: {"choices": [{"delta": {}, "finish_reason": "tool_calls"}]}` + "\n\n" +
+				"data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	client := NewOpenAICompatibleClient(server.URL, "test-key", "gpt-4o")
+
+	var toolCalls []api.ToolCall
+	err := client.GenerateInferenceWithTools(context.Background(), []Message{{Role: "user", Content: "2+2?"}},
+		func(chunk string) error { return nil },
+		func(calls []api.ToolCall) error {
+			toolCalls = calls
+			return nil
+		},
+		WithStreaming(true),
+	)
+
+	require.NoError(t, err)
+	require.Len(t, toolCalls, 2)
+	assert.Equal(t, "calculator", toolCalls[0].Function.Name)
+	assert.Equal(t, "2+2", toolCalls[0].Function.Arguments["expression"])
+	assert.Equal(t, "search", toolCalls[1].Function.Name)
+	assert.Equal(t, "go", toolCalls[1].Function.Arguments["query"])
+}
@@ -0,0 +1,41 @@
+package llm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterBurstThenWaits(t *testing.T) {
+	limiter := NewRateLimiter(50, 2)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		require.NoError(t, limiter.Wait(context.Background()))
+	}
+
+	// Two requests fit in the burst, the other two wait 20ms each
+	assert.GreaterOrEqual(t, time.Since(start), 35*time.Millisecond)
+}
+
+func TestRateLimiterContextCancel(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	require.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, limiter.Wait(ctx), context.DeadlineExceeded)
+}
+
+func TestRateLimiterSharedBetweenClients(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+
+	a := NewOpenAICompatibleClient("http://localhost", "", "a", WithRateLimiter(limiter))
+	b := NewOpenAICompatibleClient("http://localhost", "", "b", WithRateLimiter(limiter))
+
+	assert.Same(t, a.limiter, b.limiter)
+}
@@ -0,0 +1,150 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "agent.json")
+	calls := []api.ToolCall{{Function: api.ToolCallFunction{Name: "search", Arguments: api.ToolCallFunctionArguments{"query": "go"}}}}
+	messages := []Message{{Role: "user", Content: "What is Go?"}}
+
+	live := &fakeLLMClient{model: "live-model", capabilities: NativeToolCalling, chunks: []string{"Go is ", "a language"}, toolCalls: calls}
+	recorder := NewRecordingClient(live, path)
+
+	var recordedUsage Usage
+	require.NoError(t, recorder.GenerateInferenceWithTools(context.Background(), messages,
+		func(string) error { return nil },
+		func([]api.ToolCall) error { return nil },
+		WithTools([]api.Tool{{Type: "function"}}), WithUsageCallback(func(u Usage) { recordedUsage = u })))
+
+	live.toolCalls = nil
+	_, err := collect(t, recorder)
+	require.NoError(t, err)
+
+	replay, err := NewReplayClient(path)
+	require.NoError(t, err)
+	assert.Equal(t, "live-model", replay.GetModel())
+	assert.Equal(t, NativeToolCalling, replay.Capabilities())
+
+	var chunks []string
+	var toolCalls []api.ToolCall
+	require.NoError(t, replay.GenerateInferenceWithTools(context.Background(), messages,
+		func(chunk string) error {
+			chunks = append(chunks, chunk)
+			return nil
+		},
+		func(c []api.ToolCall) error {
+			toolCalls = c
+			return nil
+		},
+		WithTools([]api.Tool{{Type: "function"}})))
+
+	assert.Equal(t, []string{"Go is ", "a language"}, chunks)
+	assert.Equal(t, "search", toolCalls[0].Function.Name)
+	assert.Equal(t, "go", toolCalls[0].Function.Arguments["query"])
+	assert.Equal(t, Usage{}, recordedUsage) // the fake client reports no usage
+
+	result, err := collect(t, replay)
+	require.NoError(t, err)
+	assert.Equal(t, "Go is a language", result)
+	assert.Equal(t, 2, live.calls) // replay never calls the live client
+}
+
+func TestReplayRecordsUsageAndErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	live := &recordedUsageClient{fakeLLMClient: fakeLLMClient{err: errors.New("overloaded")}}
+
+	recorder := NewRecordingClient(live, path)
+	_, err := collect(t, recorder)
+	assert.EqualError(t, err, "overloaded")
+
+	replay, err := NewReplayClient(path)
+	require.NoError(t, err)
+
+	var usage Usage
+	err = replay.GenerateInference(context.Background(), []Message{{Role: "user", Content: "Hi"}},
+		func(string) error { return nil }, WithUsageCallback(func(u Usage) { usage = u }))
+
+	assert.EqualError(t, err, "overloaded")
+	assert.Equal(t, Usage{PromptTokens: 3, CompletionTokens: 1}, usage)
+}
+
+func TestRecordingClientNilCallback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recorder := NewRecordingClient(&fakeLLMClient{chunks: []string{"Hello"}}, path)
+
+	require.NoError(t, recorder.GenerateInference(context.Background(), []Message{{Role: "user", Content: "Hi"}}, nil))
+
+	replay, err := NewReplayClient(path)
+	require.NoError(t, err)
+	result, err := collect(t, replay)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", result)
+}
+
+func TestReplayUnmatchedRequestFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	_, err := collect(t, NewRecordingClient(&fakeLLMClient{chunks: []string{"Hello"}}, path))
+	require.NoError(t, err)
+
+	replay, err := NewReplayClient(path)
+	require.NoError(t, err)
+
+	// A different temperature is a different request
+	err = replay.GenerateInference(context.Background(), []Message{{Role: "user", Content: "Hi"}},
+		func(string) error { return nil }, WithTemperature(0.1))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no recorded interaction")
+	assert.Contains(t, err.Error(), `last message "Hi"`)
+	assert.Len(t, replay.Unmatched(), 1)
+}
+
+func TestReplayRepeatedRequestsInOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	live := &fakeLLMClient{chunks: []string{"first"}}
+	recorder := NewRecordingClient(live, path)
+
+	_, err := collect(t, recorder)
+	require.NoError(t, err)
+	live.chunks = []string{"second"}
+	_, err = collect(t, recorder)
+	require.NoError(t, err)
+
+	replay, err := NewReplayClient(path)
+	require.NoError(t, err)
+
+	for _, expected := range []string{"first", "second", "second"} {
+		result, err := collect(t, replay)
+		require.NoError(t, err)
+		assert.Equal(t, expected, result)
+	}
+}
+
+func TestNewReplayClientMissingCassette(t *testing.T) {
+	_, err := NewReplayClient(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// recordedUsageClient reports a fixed usage before failing or answering.
+type recordedUsageClient struct {
+	fakeLLMClient
+}
+
+func (c *recordedUsageClient) GenerateInference(ctx context.Context, messages []Message, callback func(chunk string) error, opts ...LLMOption) error {
+	var settings LLMSettings
+	for _, opt := range opts {
+		opt(&settings)
+	}
+	settings.ReportUsage(Usage{PromptTokens: 3, CompletionTokens: 1})
+	return c.fakeLLMClient.GenerateInference(ctx, messages, callback, opts...)
+}
@@ -0,0 +1,206 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/ollama/ollama/api"
+)
+
+// Cassette is the file format shared by RecordingClient and ReplayClient.
+type Cassette struct {
+	Model        string        `json:"model"`
+	Capabilities Capability    `json:"capabilities"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded inference call.
+type Interaction struct {
+	Key      string              `json:"key"`
+	Request  InteractionRequest  `json:"request"`
+	Response InteractionResponse `json:"response"`
+}
+
+// InteractionRequest holds the parts of a call that identify it. Streaming is not part of it,
+// so a response recorded with streaming can be replayed without it and the other way round.
+type InteractionRequest struct {
+	WithTools   bool                      `json:"with_tools"`
+	Messages    []Message                 `json:"messages"`
+	System      string                    `json:"system,omitempty"`
+	Temperature float64                   `json:"temperature"`
+	MaxTokens   int                       `json:"max_tokens"`
+	Tools       []api.Tool                `json:"tools,omitempty"`
+	ToolSchemas map[string]map[string]any `json:"tool_schemas,omitempty"`
+	JSONSchema  map[string]any            `json:"json_schema,omitempty"`
+}
+
+// InteractionResponse holds everything the wrapped client passed back, in order of arrival.
+type InteractionResponse struct {
+	Chunks    []string       `json:"chunks,omitempty"`
+	ToolCalls []api.ToolCall `json:"tool_calls,omitempty"`
+	Usage     *Usage         `json:"usage,omitempty"`
+	Error     string         `json:"error,omitempty"`
+}
+
+func newInteractionRequest(messages []Message, opts []LLMOption, withTools bool) (InteractionRequest, LLMSettings) {
+	var settings LLMSettings
+	for _, opt := range opts {
+		opt(&settings)
+	}
+
+	request := InteractionRequest{
+		WithTools:   withTools,
+		Messages:    messages,
+		System:      settings.system,
+		Temperature: settings.temperature,
+		MaxTokens:   settings.maxTokens,
+		JSONSchema:  settings.jsonSchema,
+	}
+	if withTools {
+		request.Tools = settings.tools
+		request.ToolSchemas = settings.toolSchemas
+	}
+	return request, settings
+}
+
+// key hashes the request so identical calls map to the same recorded interaction.
+func (r InteractionRequest) key() string {
+	data, err := json.Marshal(r)
+	if err != nil {
+		// Messages and tools are plain data, so this only happens with unsupported argument values
+		data = fmt.Appendf(nil, "%#v", r)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// RecordingClient wraps a client and writes every call and its response to a cassette file,
+// to be served later by a ReplayClient. The file is rewritten after every call.
+type RecordingClient struct {
+	client LLMClient
+	path   string
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+func NewRecordingClient(client LLMClient, path string) *RecordingClient {
+	return &RecordingClient{
+		client: client,
+		path:   path,
+		cassette: Cassette{
+			Model:        client.GetModel(),
+			Capabilities: client.Capabilities(),
+			Interactions: []Interaction{},
+		},
+	}
+}
+
+func (c *RecordingClient) Capabilities() Capability {
+	return c.client.Capabilities()
+}
+
+func (c *RecordingClient) GetModel() string {
+	return c.client.GetModel()
+}
+
+func (c *RecordingClient) GenerateInference(ctx context.Context, messages []Message, callback func(chunk string) error, opts ...LLMOption) error {
+	request, settings := newInteractionRequest(messages, opts, false)
+
+	var response InteractionResponse
+	err := c.client.GenerateInference(ctx, messages,
+		func(chunk string) error {
+			response.Chunks = append(response.Chunks, chunk)
+			if callback == nil {
+				return nil
+			}
+			return callback(chunk)
+		},
+		append(slices.Clip(opts), recordUsage(&response, settings))...)
+
+	return c.record(request, response, err)
+}
+
+func (c *RecordingClient) GenerateInferenceWithTools(
+	ctx context.Context,
+	messages []Message,
+	contentCallback func(chunk string) error,
+	toolCallback func(toolCalls []api.ToolCall) error,
+	opts ...LLMOption,
+) error {
+	request, settings := newInteractionRequest(messages, opts, true)
+
+	var response InteractionResponse
+	err := c.client.GenerateInferenceWithTools(ctx, messages,
+		func(chunk string) error {
+			response.Chunks = append(response.Chunks, chunk)
+			if contentCallback == nil {
+				return nil
+			}
+			return contentCallback(chunk)
+		},
+		func(toolCalls []api.ToolCall) error {
+			response.ToolCalls = append(response.ToolCalls, toolCalls...)
+			if toolCallback == nil {
+				return nil
+			}
+			return toolCallback(toolCalls)
+		},
+		append(slices.Clip(opts), recordUsage(&response, settings))...)
+
+	return c.record(request, response, err)
+}
+
+// recordUsage captures the usage reported by the wrapped client and forwards it to the caller.
+func recordUsage(response *InteractionResponse, settings LLMSettings) LLMOption {
+	return WithUsageCallback(func(usage Usage) {
+		response.Usage = &usage
+		settings.ReportUsage(usage)
+	})
+}
+
+// record appends the interaction and saves the cassette, returning callErr unless saving fails.
+func (c *RecordingClient) record(request InteractionRequest, response InteractionResponse, callErr error) error {
+	if callErr != nil {
+		response.Error = callErr.Error()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cassette.Interactions = append(c.cassette.Interactions, Interaction{
+		Key:      request.key(),
+		Request:  request,
+		Response: response,
+	})
+
+	if err := c.save(); err != nil {
+		return fmt.Errorf("error saving cassette %s: %w", c.path, err)
+	}
+	return callErr
+}
+
+func (c *RecordingClient) save() error {
+	data, err := json.MarshalIndent(c.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so an interrupted run never leaves a truncated cassette
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
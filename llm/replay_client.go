@@ -0,0 +1,146 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/ollama/ollama/api"
+)
+
+// ReplayClient serves the responses of a cassette written by RecordingClient, so agent runs can
+// be tested offline and deterministically.
+//
+// Calls are matched by a hash of their messages and options. Identical calls are answered with
+// their recorded responses in order, repeating the last one once all were used. A call that was
+// never recorded fails with an error naming the unmatched request.
+type ReplayClient struct {
+	cassette Cassette
+
+	mu        sync.Mutex
+	byKey     map[string][]Interaction
+	served    map[string]int
+	unmatched []string
+}
+
+func NewReplayClient(path string) (*ReplayClient, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cassette: %w", err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("error parsing cassette %s: %w", path, err)
+	}
+
+	byKey := make(map[string][]Interaction, len(cassette.Interactions))
+	for _, interaction := range cassette.Interactions {
+		byKey[interaction.Key] = append(byKey[interaction.Key], interaction)
+	}
+
+	return &ReplayClient{
+		cassette: cassette,
+		byKey:    byKey,
+		served:   map[string]int{},
+	}, nil
+}
+
+func (c *ReplayClient) Capabilities() Capability {
+	return c.cassette.Capabilities
+}
+
+func (c *ReplayClient) GetModel() string {
+	return c.cassette.Model
+}
+
+// Unmatched returns a description of every call that had no recorded interaction.
+func (c *ReplayClient) Unmatched() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.unmatched...)
+}
+
+func (c *ReplayClient) GenerateInference(ctx context.Context, messages []Message, callback func(chunk string) error, opts ...LLMOption) error {
+	return c.replay(messages, opts, false, callback, nil)
+}
+
+func (c *ReplayClient) GenerateInferenceWithTools(
+	ctx context.Context,
+	messages []Message,
+	contentCallback func(chunk string) error,
+	toolCallback func(toolCalls []api.ToolCall) error,
+	opts ...LLMOption,
+) error {
+	return c.replay(messages, opts, true, contentCallback, toolCallback)
+}
+
+func (c *ReplayClient) replay(
+	messages []Message,
+	opts []LLMOption,
+	withTools bool,
+	contentCallback func(chunk string) error,
+	toolCallback func(toolCalls []api.ToolCall) error,
+) error {
+	request, settings := newInteractionRequest(messages, opts, withTools)
+
+	response, err := c.next(request)
+	if err != nil {
+		return err
+	}
+
+	for _, chunk := range response.Chunks {
+		if contentCallback == nil {
+			break
+		}
+		if err := contentCallback(chunk); err != nil {
+			return err
+		}
+	}
+	if len(response.ToolCalls) > 0 && toolCallback != nil {
+		if err := toolCallback(response.ToolCalls); err != nil {
+			return err
+		}
+	}
+	if response.Usage != nil {
+		settings.ReportUsage(*response.Usage)
+	}
+
+	if response.Error != "" {
+		return errors.New(response.Error)
+	}
+	return nil
+}
+
+func (c *ReplayClient) next(request InteractionRequest) (InteractionResponse, error) {
+	key := request.key()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	interactions := c.byKey[key]
+	if len(interactions) == 0 {
+		description := describeRequest(request)
+		c.unmatched = append(c.unmatched, description)
+		return InteractionResponse{}, fmt.Errorf("replay: no recorded interaction for request %s (%s); re-record the cassette", key[:12], description)
+	}
+
+	i := min(c.served[key], len(interactions)-1)
+	c.served[key]++
+	return interactions[i].Response, nil
+}
+
+// describeRequest summarizes a request in error messages.
+func describeRequest(request InteractionRequest) string {
+	last := ""
+	if n := len(request.Messages); n > 0 {
+		last = request.Messages[n-1].Content
+		if len(last) > 80 {
+			last = last[:80] + "..."
+		}
+	}
+	return fmt.Sprintf("with_tools=%t, %d messages, last message %q", request.WithTools, len(request.Messages), last)
+}
@@ -0,0 +1,172 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SaiNageswarS/go-api-boot/logger"
+	"go.uber.org/zap"
+)
+
+// RetryPolicy controls how failed provider requests are retried with exponential backoff.
+// The zero value makes a single attempt.
+type RetryPolicy struct {
+	MaxAttempts    int           // total attempts including the first one
+	InitialBackoff time.Duration // wait before the first retry
+	MaxBackoff     time.Duration // upper bound of a single wait, including Retry-After
+	Multiplier     float64       // backoff growth per attempt; values below 1 mean 2
+	Jitter         float64       // fraction of each wait that is randomized, between 0 and 1
+
+	// Retryable decides which status codes are retried. Nil uses IsRetryableStatus.
+	Retryable func(statusCode int) bool
+}
+
+// DefaultRetryPolicy retries up to three times, waiting 0.5s, 1s and 2s with 20% jitter.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// APIError is the error of a provider request that failed with a non-200 status.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// IsRetryableStatus reports whether a status code is a transient failure: timeouts, rate limits,
+// server errors and Anthropic's 529 overloaded error.
+func IsRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout,
+		529:
+		return true
+	}
+	return false
+}
+
+func (p RetryPolicy) retryable(statusCode int) bool {
+	if p.Retryable != nil {
+		return p.Retryable(statusCode)
+	}
+	return IsRetryableStatus(statusCode)
+}
+
+// backoff returns the wait before retry number attempt, starting at 1.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	wait := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 {
+		wait = min(wait, float64(p.MaxBackoff))
+	}
+	if jitter := min(max(p.Jitter, 0), 1); jitter > 0 {
+		wait *= 1 - jitter + 2*jitter*rand.Float64()
+	}
+	return time.Duration(wait)
+}
+
+// doWithRetry sends the request built by newRequest, waiting for limiter before every attempt.
+// Network errors and retryable statuses are retried according to policy, honoring Retry-After up
+// to the policy's MaxBackoff.
+// A successful response is returned unread; a failed one is closed and described in an *APIError.
+func doWithRetry(
+	ctx context.Context,
+	httpClient *http.Client,
+	policy RetryPolicy,
+	limiter *RateLimiter,
+	newRequest func() (*http.Request, error),
+) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		if limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		req, err := newRequest()
+		if err != nil {
+			return nil, fmt.Errorf("error creating request: %w", err)
+		}
+
+		wait := policy.backoff(attempt)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			err = fmt.Errorf("error making request: %w", err)
+			if ctx.Err() != nil {
+				return nil, err
+			}
+		} else if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		} else {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			err = &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+
+			if !policy.retryable(resp.StatusCode) {
+				return nil, err
+			}
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				// Never wait longer than the policy allows; the attempt budget still bounds the retries
+				wait = retryAfter
+				if policy.MaxBackoff > 0 {
+					wait = min(wait, policy.MaxBackoff)
+				}
+			}
+		}
+
+		if attempt >= policy.MaxAttempts {
+			return nil, err
+		}
+
+		logger.Info("Retrying LLM request",
+			zap.Int("attempt", attempt), zap.Duration("wait", wait), zap.Error(err))
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
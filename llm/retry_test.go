@@ -0,0 +1,179 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fastRetryPolicy(maxAttempts int) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     50 * time.Millisecond,
+		Multiplier:     2,
+	}
+}
+
+func TestAnthropicClientRetriesOverloaded(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) < 3 {
+			http.Error(w, `{"type": "error", "error": {"type": "overloaded_error"}}`, 529)
+			return
+		}
+		_, _ = w.Write([]byte(`{"content": [{"type": "text", "text": "Recovered"}]}`))
+	}))
+	defer server.Close()
+
+	client := &AnthropicClient{apiKey: "test-key", httpClient: server.Client(), url: server.URL, retry: fastRetryPolicy(3)}
+
+	var result string
+	err := client.GenerateInference(context.Background(), []Message{{Role: "user", Content: "Hi"}}, func(chunk string) error {
+		result = chunk
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Recovered", result)
+	assert.Equal(t, int32(3), attempts.Load())
+}
+
+func TestOpenAICompatibleClientGivesUpAfterMaxAttempts(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewOpenAICompatibleClient(server.URL, "key", "model", WithRetryPolicy(fastRetryPolicy(2)))
+
+	err := client.GenerateInference(context.Background(), []Message{{Role: "user", Content: "Hi"}}, func(string) error { return nil })
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 429")
+	assert.Equal(t, int32(2), attempts.Load())
+}
+
+func TestDoWithRetryDoesNotRetryClientErrors(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	_, err := doWithRetry(context.Background(), server.Client(), fastRetryPolicy(5), nil, func() (*http.Request, error) {
+		return http.NewRequest("POST", server.URL, nil)
+	})
+
+	assert.EqualError(t, err, "API request failed with status 400: bad request\n")
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestDoWithRetryHonorsRetryAfter(t *testing.T) {
+	var attempts atomic.Int32
+	var firstAttempt time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) == 1 {
+			firstAttempt = time.Now()
+			w.Header().Set("Retry-After", "0.03")
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+			return
+		}
+		assert.GreaterOrEqual(t, time.Since(firstAttempt), 30*time.Millisecond)
+	}))
+	defer server.Close()
+
+	resp, err := doWithRetry(context.Background(), server.Client(), fastRetryPolicy(2), nil, func() (*http.Request, error) {
+		return http.NewRequest("POST", server.URL, nil)
+	})
+
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, int32(2), attempts.Load())
+}
+
+func TestDoWithRetryClampsRetryAfterToMaxBackoff(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		http.Error(w, "quota exhausted", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	_, err := doWithRetry(context.Background(), server.Client(), fastRetryPolicy(3), nil, func() (*http.Request, error) {
+		return http.NewRequest("POST", server.URL, nil)
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Less(t, time.Since(start), 5*time.Second) // two waits of MaxBackoff, not an hour
+}
+
+func TestDoWithRetryStopsOnContextCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	policy := RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Second}
+	_, err := doWithRetry(ctx, server.Client(), policy, nil, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "POST", server.URL, nil)
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 3}
+
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 300*time.Millisecond, policy.backoff(2))
+	assert.Equal(t, 900*time.Millisecond, policy.backoff(3))
+	assert.Equal(t, time.Second, policy.backoff(4)) // capped
+
+	policy.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		wait := policy.backoff(1)
+		assert.GreaterOrEqual(t, wait, 50*time.Millisecond)
+		assert.LessOrEqual(t, wait, 150*time.Millisecond)
+	}
+}
+
+func TestIsRetryableStatus(t *testing.T) {
+	for _, code := range []int{408, 429, 500, 502, 503, 504, 529} {
+		assert.True(t, IsRetryableStatus(code), code)
+	}
+	for _, code := range []int{200, 400, 401, 403, 404, 422} {
+		assert.False(t, IsRetryableStatus(code), code)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	wait, ok := parseRetryAfter("2")
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, wait)
+
+	wait, ok = parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.InDelta(t, time.Minute, wait, float64(2*time.Second))
+
+	_, ok = parseRetryAfter("soon")
+	assert.False(t, ok)
+}
@@ -0,0 +1,126 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/ollama/ollama/api"
+)
+
+// RouteInfo describes an inference call to the rules of a RouterClient.
+type RouteInfo struct {
+	Messages []Message
+	System   string     // system prompt set with WithSystemPrompt
+	Tools    []api.Tool // tools set with WithTools; only passed by GenerateInferenceWithTools
+	Required Capability // capabilities the call needs, NativeToolCalling when tools are passed
+}
+
+// Length returns the number of characters of the system prompt and messages.
+func (i RouteInfo) Length() int {
+	n := len(i.System)
+	for _, msg := range i.Messages {
+		n += len(msg.Content)
+	}
+	return n
+}
+
+// RouteRule decides whether a route serves a call.
+type RouteRule func(info RouteInfo) bool
+
+// Route sends the calls matching Rule to Client.
+type Route struct {
+	Rule   RouteRule
+	Client LLMClient
+}
+
+// HasTools matches calls that pass tools.
+func HasTools() RouteRule {
+	return func(info RouteInfo) bool { return len(info.Tools) > 0 }
+}
+
+// LongerThan matches calls whose prompt has more than chars characters.
+func LongerThan(chars int) RouteRule {
+	return func(info RouteInfo) bool { return info.Length() > chars }
+}
+
+// Requires matches calls that need all of the given capabilities.
+func Requires(capabilities Capability) RouteRule {
+	return func(info RouteInfo) bool { return info.Required&capabilities == capabilities }
+}
+
+// AllOf matches calls matched by every rule.
+func AllOf(rules ...RouteRule) RouteRule {
+	return func(info RouteInfo) bool {
+		for _, rule := range rules {
+			if !rule(info) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// RouterClient chooses the client serving each call from a list of routes, e.g. sending tool
+// selection to a model with native tool calling and long prompts to a large context model.
+//
+// The first route whose rule matches and whose client has the capabilities the call requires
+// is used; calls matching no route go to the default client.
+type RouterClient struct {
+	defaultClient LLMClient
+	routes        []Route
+}
+
+func NewRouterClient(defaultClient LLMClient, routes ...Route) *RouterClient {
+	return &RouterClient{defaultClient: defaultClient, routes: routes}
+}
+
+// Capabilities returns the capabilities of any of the clients, since calls are routed to a client
+// that has the capabilities they need.
+func (c *RouterClient) Capabilities() Capability {
+	capabilities := c.defaultClient.Capabilities()
+	for _, route := range c.routes {
+		capabilities |= route.Client.Capabilities()
+	}
+	return capabilities
+}
+
+// GetModel returns the model of the default client. The usage reported to WithUsageCallback names
+// the model that served each call.
+func (c *RouterClient) GetModel() string {
+	return c.defaultClient.GetModel()
+}
+
+func (c *RouterClient) GenerateInference(ctx context.Context, messages []Message, callback func(chunk string) error, opts ...LLMOption) error {
+	client := c.route(messages, opts, false)
+	return client.GenerateInference(ctx, messages, callback, opts...)
+}
+
+func (c *RouterClient) GenerateInferenceWithTools(
+	ctx context.Context,
+	messages []Message,
+	contentCallback func(chunk string) error,
+	toolCallback func(toolCalls []api.ToolCall) error,
+	opts ...LLMOption,
+) error {
+	client := c.route(messages, opts, true)
+	return client.GenerateInferenceWithTools(ctx, messages, contentCallback, toolCallback, opts...)
+}
+
+func (c *RouterClient) route(messages []Message, opts []LLMOption, withTools bool) LLMClient {
+	var settings LLMSettings
+	for _, opt := range opts {
+		opt(&settings)
+	}
+
+	info := RouteInfo{Messages: messages, System: settings.system}
+	if withTools && len(settings.tools) > 0 {
+		info.Tools = settings.tools
+		info.Required |= NativeToolCalling
+	}
+
+	for _, route := range c.routes {
+		if route.Client.Capabilities()&info.Required == info.Required && route.Rule(info) {
+			return route.Client
+		}
+	}
+	return c.defaultClient
+}
@@ -0,0 +1,96 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterClientRoutesToolCalls(t *testing.T) {
+	local := &fakeLLMClient{model: "local", chunks: []string{"local"}}
+	toolModel := &fakeLLMClient{model: "tools", capabilities: NativeToolCalling, chunks: []string{"tools"}}
+
+	router := NewRouterClient(local, Route{Rule: HasTools(), Client: toolModel})
+
+	// Plain inference goes to the default client
+	result, err := collect(t, router)
+	require.NoError(t, err)
+	assert.Equal(t, "local", result)
+
+	err = router.GenerateInferenceWithTools(context.Background(), nil,
+		func(chunk string) error {
+			result = chunk
+			return nil
+		},
+		func([]api.ToolCall) error { return nil },
+		WithTools([]api.Tool{{Type: "function"}}))
+
+	require.NoError(t, err)
+	assert.Equal(t, "tools", result)
+}
+
+func TestRouterClientRoutesLongPrompts(t *testing.T) {
+	small := &fakeLLMClient{model: "small", chunks: []string{"small"}}
+	large := &fakeLLMClient{model: "large", chunks: []string{"large"}}
+
+	router := NewRouterClient(small, Route{Rule: LongerThan(100), Client: large})
+
+	var result string
+	callback := func(chunk string) error {
+		result = chunk
+		return nil
+	}
+
+	require.NoError(t, router.GenerateInference(context.Background(), []Message{{Role: "user", Content: "short"}}, callback))
+	assert.Equal(t, "small", result)
+
+	require.NoError(t, router.GenerateInference(context.Background(), []Message{{Role: "user", Content: strings.Repeat("x", 101)}}, callback))
+	assert.Equal(t, "large", result)
+
+	// The system prompt counts towards the length
+	require.NoError(t, router.GenerateInference(context.Background(), []Message{{Role: "user", Content: "short"}}, callback,
+		WithSystemPrompt(strings.Repeat("x", 100))))
+	assert.Equal(t, "large", result)
+}
+
+func TestRouterClientSkipsRoutesMissingCapabilities(t *testing.T) {
+	fallback := &fakeLLMClient{model: "fallback", capabilities: NativeToolCalling}
+	noTools := &fakeLLMClient{model: "no-tools"}
+
+	router := NewRouterClient(fallback, Route{Rule: func(RouteInfo) bool { return true }, Client: noTools})
+
+	err := router.GenerateInferenceWithTools(context.Background(), nil,
+		func(string) error { return nil },
+		func([]api.ToolCall) error { return nil },
+		WithTools([]api.Tool{{Type: "function"}}))
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, noTools.calls)
+	assert.Equal(t, 1, fallback.calls)
+}
+
+func TestRouteRules(t *testing.T) {
+	info := RouteInfo{
+		Messages: []Message{{Content: "hello"}},
+		Tools:    []api.Tool{{Type: "function"}},
+		Required: NativeToolCalling,
+	}
+
+	assert.True(t, HasTools()(info))
+	assert.True(t, Requires(NativeToolCalling)(info))
+	assert.True(t, AllOf(HasTools(), LongerThan(4))(info))
+	assert.False(t, AllOf(HasTools(), LongerThan(5))(info))
+	assert.False(t, HasTools()(RouteInfo{}))
+}
+
+func TestRouterClientCapabilities(t *testing.T) {
+	router := NewRouterClient(&fakeLLMClient{model: "default"},
+		Route{Rule: HasTools(), Client: &fakeLLMClient{capabilities: NativeToolCalling}})
+
+	assert.Equal(t, NativeToolCalling, router.Capabilities())
+	assert.Equal(t, "default", router.GetModel())
+}
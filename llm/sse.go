@@ -0,0 +1,64 @@
+package llm
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// maxSSELineSize bounds a single server-sent-event line; tool call arguments can be large.
+const maxSSELineSize = 1024 * 1024
+
+// readSSE parses a server-sent-event stream and calls fn once per event with its type and data.
+// Multi-line data fields are joined with newlines and comment lines are ignored.
+// Returning io.EOF from fn stops reading without an error.
+func readSSE(r io.Reader, fn func(event, data string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSSELineSize)
+
+	var event string
+	var data []string
+
+	dispatch := func() error {
+		defer func() { event, data = "", nil }()
+		if len(data) == 0 {
+			return nil
+		}
+		return fn(event, strings.Join(data, "\n"))
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if err := dispatch(); err != nil {
+				return ignoreEOF(err)
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	// The stream may end without a trailing blank line
+	return ignoreEOF(dispatch())
+}
+
+func ignoreEOF(err error) error {
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
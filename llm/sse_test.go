@@ -0,0 +1,48 @@
+package llm
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSSE(t *testing.T) {
+	stream := ": comment\n" +
+		"event: first\n" +
+		"data: line one\n" +
+		"data: line two\n" +
+		"\n" +
+		"data:no-space\n" +
+		"\n" +
+		"event: ignored\n" +
+		"\n" +
+		"data: trailing"
+
+	type event struct{ name, data string }
+	var events []event
+	err := readSSE(strings.NewReader(stream), func(name, data string) error {
+		events = append(events, event{name, data})
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []event{
+		{"first", "line one\nline two"},
+		{"", "no-space"},
+		{"", "trailing"},
+	}, events)
+}
+
+func TestReadSSEStopsOnEOF(t *testing.T) {
+	calls := 0
+	err := readSSE(strings.NewReader("data: a\n\ndata: b\n\n"), func(_, _ string) error {
+		calls++
+		return io.EOF
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
@@ -2,12 +2,22 @@ package memory
 
 import (
 	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/ollama/ollama/api"
 )
 
 // Conversation represents a conversation session with messages
 type Conversation struct {
-	ID       string        `bson:"_id"`
-	Messages []llm.Message `bson:"messages"`
+	ID        string        `bson:"_id" json:"id"`
+	UserID    string        `bson:"userId,omitempty" json:"userId,omitempty"` // owner of the session, empty when sessions aren't scoped to users
+	Messages  []llm.Message `bson:"messages" json:"messages"`
+	UpdatedAt int64         `bson:"updatedAt,omitempty" json:"updatedAt,omitempty"` // unix milliseconds of the last save
+
+	// Summary is the running summary of earlier turns removed from Messages, see
+	// ConversationManager.SetSummarizer.
+	Summary string `bson:"summary,omitempty" json:"summary,omitempty"`
+
+	// Turns records how each question was answered, oldest first.
+	Turns []TurnRecord `bson:"turns,omitempty" json:"turns,omitempty"`
 }
 
 func (m Conversation) Id() string {
@@ -18,6 +28,28 @@ func (m Conversation) CollectionName() string {
 	return "conversations"
 }
 
+// Title returns the first user message, which names the session in listings.
+func (m Conversation) Title() string {
+	for _, msg := range m.Messages {
+		if msg.Role == "user" && !msg.IsToolResult {
+			return msg.Content
+		}
+	}
+	return ""
+}
+
+// ContextMessages returns the messages to send to a model: a message with the Summary of earlier
+// turns when there is one, followed by Messages.
+func (m Conversation) ContextMessages() []llm.Message {
+	if m.Summary == "" {
+		return m.Messages
+	}
+
+	msgs := make([]llm.Message, 0, len(m.Messages)+1)
+	msgs = append(msgs, llm.Message{Role: "user", Content: summaryMessagePrefix + m.Summary})
+	return append(msgs, m.Messages...)
+}
+
 func (m *Conversation) AddUserMessage(content string) {
 	m.Messages = append(m.Messages, llm.Message{Role: "user", Content: content})
 }
@@ -26,6 +58,18 @@ func (m *Conversation) AddAssistantMessage(content string) {
 	m.Messages = append(m.Messages, llm.Message{Role: "assistant", Content: content})
 }
 
-func (m *Conversation) AddToolResult(content string) {
-	m.Messages = append(m.Messages, llm.Message{Role: "user", Content: content, IsToolResult: true})
+// AddTurn records how a question was answered.
+func (m *Conversation) AddTurn(turn TurnRecord) {
+	m.Turns = append(m.Turns, turn)
+}
+
+// AddToolCalls records the tool calls selected by the model as an assistant message.
+func (m *Conversation) AddToolCalls(calls []api.ToolCall) {
+	m.Messages = append(m.Messages, llm.Message{Role: "assistant", ToolCalls: calls})
+}
+
+// AddToolResult records the result of a tool call as a "tool" message. Results must follow
+// the AddToolCalls message in the order of its calls, one per call.
+func (m *Conversation) AddToolResult(toolName, content string) {
+	m.Messages = append(m.Messages, llm.Message{Role: "tool", Content: content, ToolName: toolName})
 }
@@ -2,54 +2,120 @@ package memory
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/SaiNageswarS/agent-boot/prompts"
 	"github.com/SaiNageswarS/go-api-boot/logger"
 	"github.com/SaiNageswarS/go-api-boot/odm"
-	"github.com/SaiNageswarS/go-collection-boot/async"
 	"go.uber.org/zap"
 )
 
+// Errors returned by the session methods of ConversationManager.
+var (
+	// ErrSessionNotFound is returned for sessions that don't exist or belong to another user.
+	ErrSessionNotFound   = errors.New("session not found")
+	ErrSessionExists     = errors.New("session already exists")
+	ErrSessionsNotStored = errors.New("sessions are not stored")
+	ErrInvalidPageToken  = errors.New("invalid page token")
+)
+
+// Page sizes of ListSessions.
+const (
+	DefaultSessionPageSize = 20
+	MaxSessionPageSize     = 100
+)
+
 // ConversationManager handles conversation-related operations
 type ConversationManager struct {
-	collection odm.OdmCollectionInterface[Conversation]
+	store      Store
 	maxMsgs    int
+	summarizer *summarizer
+	prompts    *prompts.Registry
 }
 
-// NewConversationManager creates a new conversation manager
+// NewConversationManager creates a new conversation manager storing sessions in an odm
+// collection. Without a collection, sessions are not stored.
 func NewConversationManager(collection odm.OdmCollectionInterface[Conversation], maxMsgs int) *ConversationManager {
+	if collection == nil {
+		return NewConversationManagerWithStore(nil, maxMsgs)
+	}
+	return NewConversationManagerWithStore(NewOdmStore(collection), maxMsgs)
+}
+
+// NewConversationManagerWithStore creates a conversation manager storing sessions in store.
+// Without a store, sessions are not stored.
+func NewConversationManagerWithStore(store Store, maxMsgs int) *ConversationManager {
 	return &ConversationManager{
-		collection: collection,
-		maxMsgs:    maxMsgs,
+		store:   store,
+		maxMsgs: maxMsgs,
 	}
 }
 
 // LoadSession loads previous conversation messages for a session
 func (cm *ConversationManager) LoadSession(ctx context.Context, sessionID string) *Conversation {
-	if cm.collection == nil {
-		return &Conversation{}
+	if cm.store == nil {
+		return &Conversation{ID: sessionID}
 	}
 
-	session, err := async.Await(cm.collection.FindOneByID(ctx, sessionID))
+	session, err := cm.findSession(ctx, sessionID)
 	if err != nil {
 		logger.Error("Failed to find session", zap.Error(err))
-		return &Conversation{} // Return empty slice instead of error to allow conversation to continue
+		return &Conversation{ID: sessionID} // Return empty conversation instead of error to allow conversation to continue
+	}
+	if session == nil {
+		return &Conversation{ID: sessionID}
 	}
 
 	return session
 }
 
+// LoadUserSession loads a session of userID, starting a new one when it doesn't exist yet.
+// It returns ErrSessionNotFound when the session belongs to another user. A session saved before
+// sessions had owners is claimed by the first user loading it.
+func (cm *ConversationManager) LoadUserSession(ctx context.Context, userID, sessionID string) (*Conversation, error) {
+	if cm.store == nil {
+		return &Conversation{ID: sessionID, UserID: userID}, nil
+	}
+
+	session, err := cm.findSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return &Conversation{ID: sessionID, UserID: userID}, nil
+	}
+	if err := cm.claimSession(ctx, session, userID); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
 // SaveSession saves the conversation messages for a session
 func (cm *ConversationManager) SaveSession(ctx context.Context, conversation *Conversation) error {
-	if cm.collection == nil {
+	if cm.store == nil {
 		return nil
 	}
 
+	// Fold older turns into the summary before trimming would drop them
+	if cm.summarizer != nil {
+		if err := cm.summarizer.summarize(ctx, cm.prompts, conversation); err != nil {
+			logger.Error("Failed to summarize session", zap.String("session_id", conversation.ID), zap.Error(err))
+		}
+	}
+
 	// Trim messages to respect max session limit
 	conversation.Messages = cm.trimForSession(conversation.Messages)
+	conversation.Turns = cm.trimTurns(conversation.Turns)
+	conversation.UpdatedAt = time.Now().UnixMilli()
 
-	_, err := async.Await(cm.collection.Save(ctx, *conversation))
-	if err != nil {
+	if err := cm.store.Save(ctx, *conversation); err != nil {
 		logger.Error("Failed to save session", zap.Error(err))
 		return err
 	}
@@ -57,6 +123,175 @@ func (cm *ConversationManager) SaveSession(ctx context.Context, conversation *Co
 	return nil
 }
 
+// ListSessions returns a page of the sessions of userID, most recently updated first, and the
+// token of the next page, which is empty on the last page.
+func (cm *ConversationManager) ListSessions(ctx context.Context, userID string, pageSize int, pageToken string) ([]Conversation, string, error) {
+	if cm.store == nil {
+		return nil, "", ErrSessionsNotStored
+	}
+
+	if pageSize <= 0 {
+		pageSize = DefaultSessionPageSize
+	}
+	pageSize = min(pageSize, MaxSessionPageSize)
+
+	offset := 0
+	if pageToken != "" {
+		var err error
+		if offset, err = strconv.Atoi(pageToken); err != nil || offset < 0 {
+			return nil, "", ErrInvalidPageToken
+		}
+	}
+
+	// Fetch one extra session to know whether there is a next page
+	sessions, err := cm.store.List(ctx, userID, pageSize+1, offset)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextPageToken := ""
+	if len(sessions) > pageSize {
+		sessions = sessions[:pageSize]
+		nextPageToken = strconv.Itoa(offset + pageSize)
+	}
+	return sessions, nextPageToken, nil
+}
+
+// GetSession returns a session of userID, or ErrSessionNotFound. Like LoadUserSession, it lets
+// userID claim a session without an owner.
+func (cm *ConversationManager) GetSession(ctx context.Context, userID, sessionID string) (*Conversation, error) {
+	if cm.store == nil {
+		return nil, ErrSessionsNotStored
+	}
+
+	session, err := cm.findSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrSessionNotFound
+	}
+	if err := cm.claimSession(ctx, session, userID); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// claimSession checks that session belongs to userID, returning ErrSessionNotFound otherwise.
+// Sessions saved before they had owners have no UserID; the first user to access one becomes
+// its owner, and the claim is saved so it shows up in that user's ListSessions from then on.
+func (cm *ConversationManager) claimSession(ctx context.Context, session *Conversation, userID string) error {
+	if session.UserID == userID {
+		return nil
+	}
+	if session.UserID != "" {
+		return ErrSessionNotFound
+	}
+
+	session.UserID = userID
+	if err := cm.store.Save(ctx, *session); err != nil {
+		return err
+	}
+	logger.Info("Claimed session without an owner", zap.String("session_id", session.ID), zap.String("user_id", userID))
+	return nil
+}
+
+// DeleteSession deletes a session of userID, or returns ErrSessionNotFound.
+func (cm *ConversationManager) DeleteSession(ctx context.Context, userID, sessionID string) error {
+	if _, err := cm.GetSession(ctx, userID, sessionID); err != nil {
+		return err
+	}
+
+	return cm.store.Delete(ctx, sessionID)
+}
+
+// ForkSession copies the summary and the first messageCount messages of a session of userID into
+// a new session named newSessionID, so the conversation can continue from an earlier message. A messageCount
+// of zero or more than the session has copies all messages, and an empty newSessionID gets a
+// random one. The copy ends at a user message boundary, so a messageCount splitting an answer
+// is moved back to its question, never separating tool calls from their results. The records of
+// the turns answered in the copy are kept. It returns ErrSessionExists when newSessionID is taken.
+func (cm *ConversationManager) ForkSession(ctx context.Context, userID, sessionID string, messageCount int, newSessionID string) (*Conversation, error) {
+	source, err := cm.GetSession(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if newSessionID == "" {
+		if newSessionID, err = randomID(); err != nil {
+			return nil, err
+		}
+	} else {
+		existing, err := cm.findSession(ctx, newSessionID)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, ErrSessionExists
+		}
+	}
+
+	if messageCount <= 0 || messageCount > len(source.Messages) {
+		messageCount = len(source.Messages)
+	}
+	messageCount = forkPoint(source.Messages, messageCount)
+
+	fork := &Conversation{
+		ID:       newSessionID,
+		UserID:   userID,
+		Messages: slices.Clone(source.Messages[:messageCount]),
+		Summary:  source.Summary,
+		Turns:    cloneTurns(forkTurns(source, messageCount)),
+	}
+	if err := cm.SaveSession(ctx, fork); err != nil {
+		return nil, err
+	}
+	return fork, nil
+}
+
+// forkPoint moves count back until msgs[:count] ends with a user message or right before one.
+func forkPoint(msgs []llm.Message, count int) int {
+	for count > 0 && count < len(msgs) && !isUserMessage(msgs[count]) && !isUserMessage(msgs[count-1]) {
+		count--
+	}
+	return count
+}
+
+// forkTurns returns the turn records of source whose answers are in its first messageCount
+// messages. Records belong to the latest questions, so those of later questions are dropped.
+func forkTurns(source *Conversation, messageCount int) []TurnRecord {
+	dropped := answeredQuestions(source.Messages) - answeredQuestions(source.Messages[:messageCount])
+	return source.Turns[:max(len(source.Turns)-dropped, 0)]
+}
+
+// answeredQuestions counts the user messages of msgs followed by an answer.
+func answeredQuestions(msgs []llm.Message) int {
+	answered := 0
+	for i := 0; i+1 < len(msgs); i++ {
+		if isUserMessage(msgs[i]) && !isUserMessage(msgs[i+1]) {
+			answered++
+		}
+	}
+	return answered
+}
+
+// findSession returns the session with the given ID, or nil when there is none.
+func (cm *ConversationManager) findSession(ctx context.Context, sessionID string) (*Conversation, error) {
+	session, err := cm.store.Get(ctx, sessionID)
+	if errors.Is(err, ErrSessionNotFound) {
+		return nil, nil
+	}
+	return session, err
+}
+
+func randomID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
 // trimForSession keeps the last maxMsgs "user" messages and any number of
 // "assistant" (and optional "tool") messages that follow them.
 // If there are fewer than maxMsgs user messages total, it returns msgs unchanged.
@@ -82,11 +317,24 @@ func (cm *ConversationManager) trimForSession(msgs []llm.Message) []llm.Message
 	return msgs[start:]
 }
 
+// trimTurns keeps the records of the last maxMsgs turns, like trimForSession keeps their messages.
+func (cm *ConversationManager) trimTurns(turns []TurnRecord) []TurnRecord {
+	if cm.maxMsgs <= 0 {
+		return nil
+	}
+	return turns[max(len(turns)-cm.maxMsgs, 0):]
+}
+
 // GetMaxMessages returns the maximum number of messages allowed in a session
 func (cm *ConversationManager) GetMaxMessages() int {
 	return cm.maxMsgs
 }
 
+// SetPrompts renders the summarization prompts with registry instead of the embedded templates.
+func (cm *ConversationManager) SetPrompts(registry *prompts.Registry) {
+	cm.prompts = registry
+}
+
 // SetMaxMessages sets the maximum number of messages allowed in a session
 func (cm *ConversationManager) SetMaxMessages(max int) {
 	cm.maxMsgs = max
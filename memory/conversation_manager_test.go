@@ -0,0 +1,185 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SaiNageswarS/agent-boot/internal/memorytest"
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/SaiNageswarS/agent-boot/memory"
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversationManager_LoadUserSession(t *testing.T) {
+	ctx := context.Background()
+	cm := memory.NewConversationManager(memorytest.NewCollection(
+		memory.Conversation{ID: "s1", UserID: "alice", Messages: []llm.Message{{Role: "user", Content: "Hi"}}},
+	), 10)
+
+	session, err := cm.LoadUserSession(ctx, "alice", "s1")
+	require.NoError(t, err)
+	assert.Len(t, session.Messages, 1)
+
+	session, err = cm.LoadUserSession(ctx, "alice", "new")
+	require.NoError(t, err)
+	assert.Equal(t, "new", session.ID)
+	assert.Equal(t, "alice", session.UserID)
+	assert.Empty(t, session.Messages)
+
+	_, err = cm.LoadUserSession(ctx, "bob", "s1")
+	assert.ErrorIs(t, err, memory.ErrSessionNotFound)
+}
+
+func TestConversationManager_ListSessions(t *testing.T) {
+	ctx := context.Background()
+	cm := memory.NewConversationManager(memorytest.NewCollection(
+		memory.Conversation{ID: "a", UserID: "alice", UpdatedAt: 1},
+		memory.Conversation{ID: "b", UserID: "alice", UpdatedAt: 3},
+		memory.Conversation{ID: "c", UserID: "alice", UpdatedAt: 2},
+		memory.Conversation{ID: "d", UserID: "bob", UpdatedAt: 4},
+	), 10)
+
+	sessions, token, err := cm.ListSessions(ctx, "alice", 2, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, sessionIDs(sessions))
+	require.NotEmpty(t, token)
+
+	sessions, token, err = cm.ListSessions(ctx, "alice", 2, token)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, sessionIDs(sessions))
+	assert.Empty(t, token)
+
+	_, _, err = cm.ListSessions(ctx, "alice", 2, "not-a-token")
+	assert.ErrorIs(t, err, memory.ErrInvalidPageToken)
+
+	_, _, err = memory.NewConversationManager(nil, 10).ListSessions(ctx, "alice", 0, "")
+	assert.ErrorIs(t, err, memory.ErrSessionsNotStored)
+}
+
+func TestConversationManager_GetAndDeleteSession(t *testing.T) {
+	ctx := context.Background()
+	cm := memory.NewConversationManager(memorytest.NewCollection(
+		memory.Conversation{ID: "s1", UserID: "alice"},
+	), 10)
+
+	_, err := cm.GetSession(ctx, "bob", "s1")
+	assert.ErrorIs(t, err, memory.ErrSessionNotFound)
+	assert.ErrorIs(t, cm.DeleteSession(ctx, "bob", "s1"), memory.ErrSessionNotFound)
+
+	require.NoError(t, cm.DeleteSession(ctx, "alice", "s1"))
+	_, err = cm.GetSession(ctx, "alice", "s1")
+	assert.ErrorIs(t, err, memory.ErrSessionNotFound)
+}
+
+func TestConversationManager_ClaimsSessionsWithoutOwner(t *testing.T) {
+	ctx := context.Background()
+	collection := memorytest.NewCollection(
+		memory.Conversation{ID: "legacy", Messages: []llm.Message{{Role: "user", Content: "Hi"}}},
+		memory.Conversation{ID: "legacy-get"},
+	)
+	cm := memory.NewConversationManager(collection, 10)
+
+	// The first user to load a session without an owner claims it
+	session, err := cm.LoadUserSession(ctx, "alice", "legacy")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", session.UserID)
+	assert.Len(t, session.Messages, 1)
+
+	_, err = cm.LoadUserSession(ctx, "bob", "legacy")
+	assert.ErrorIs(t, err, memory.ErrSessionNotFound)
+
+	sessions, _, err := cm.ListSessions(ctx, "alice", 10, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"legacy"}, sessionIDs(sessions))
+
+	// The session RPCs claim them too
+	_, err = cm.ForkSession(ctx, "bob", "legacy-get", 0, "bob-fork")
+	require.NoError(t, err)
+	assert.ErrorIs(t, cm.DeleteSession(ctx, "alice", "legacy-get"), memory.ErrSessionNotFound)
+	require.NoError(t, cm.DeleteSession(ctx, "bob", "legacy-get"))
+}
+
+func TestConversationManager_ForkSession(t *testing.T) {
+	ctx := context.Background()
+	cm := memory.NewConversationManager(memorytest.NewCollection(
+		memory.Conversation{ID: "s1", UserID: "alice", Messages: []llm.Message{
+			{Role: "user", Content: "Hi"},
+			{Role: "assistant", Content: "Hello"},
+			{Role: "user", Content: "Bye"},
+		}},
+		memory.Conversation{ID: "taken", UserID: "bob"},
+	), 10)
+
+	fork, err := cm.ForkSession(ctx, "alice", "s1", 2, "s2")
+	require.NoError(t, err)
+	assert.Equal(t, "s2", fork.ID)
+	assert.Len(t, fork.Messages, 2)
+
+	saved, err := cm.GetSession(ctx, "alice", "s2")
+	require.NoError(t, err)
+	assert.Equal(t, fork.Messages, saved.Messages)
+
+	source, err := cm.GetSession(ctx, "alice", "s1")
+	require.NoError(t, err)
+	assert.Len(t, source.Messages, 3, "Forking must not change the source session")
+
+	fork, err = cm.ForkSession(ctx, "alice", "s1", 0, "")
+	require.NoError(t, err)
+	assert.NotEmpty(t, fork.ID)
+	assert.Len(t, fork.Messages, 3)
+
+	_, err = cm.ForkSession(ctx, "alice", "s1", 1, "taken")
+	assert.ErrorIs(t, err, memory.ErrSessionExists)
+
+	_, err = cm.ForkSession(ctx, "bob", "s1", 1, "")
+	assert.ErrorIs(t, err, memory.ErrSessionNotFound)
+}
+
+func sessionIDs(sessions []memory.Conversation) []string {
+	ids := make([]string, len(sessions))
+	for i, session := range sessions {
+		ids[i] = session.ID
+	}
+	return ids
+}
+
+func TestConversationManager_ForkSessionKeepsToolCallsWithResults(t *testing.T) {
+	ctx := context.Background()
+	cm := memory.NewConversationManager(memorytest.NewCollection(
+		memory.Conversation{ID: "s1", UserID: "alice", Messages: []llm.Message{
+			{Role: "user", Content: "What is 2+2?"},
+			{Role: "assistant", ToolCalls: []api.ToolCall{{Function: api.ToolCallFunction{Name: "add"}}}},
+			{Role: "tool", ToolName: "add", Content: "4"},
+			{Role: "assistant", Content: "4"},
+			{Role: "user", Content: "And 3+3?"},
+			{Role: "assistant", ToolCalls: []api.ToolCall{{Function: api.ToolCallFunction{Name: "add"}}}},
+			{Role: "tool", ToolName: "add", Content: "6"},
+			{Role: "assistant", Content: "6"},
+		}, Turns: []memory.TurnRecord{{Question: "What is 2+2?"}, {Question: "And 3+3?"}}},
+	), 10)
+
+	tests := []struct {
+		messageCount  int
+		wantMessages  int
+		wantQuestions []string
+	}{
+		{messageCount: 6, wantMessages: 5, wantQuestions: []string{"What is 2+2?"}}, // Between the tool call and its result
+		{messageCount: 7, wantMessages: 5, wantQuestions: []string{"What is 2+2?"}}, // Between the result and the answer
+		{messageCount: 4, wantMessages: 4, wantQuestions: []string{"What is 2+2?"}},
+		{messageCount: 2, wantMessages: 1},
+		{messageCount: 0, wantMessages: 8, wantQuestions: []string{"What is 2+2?", "And 3+3?"}},
+	}
+	for _, tt := range tests {
+		fork, err := cm.ForkSession(ctx, "alice", "s1", tt.messageCount, "")
+		require.NoError(t, err)
+		assert.Len(t, fork.Messages, tt.wantMessages, "messageCount %d", tt.messageCount)
+
+		var questions []string
+		for _, turn := range fork.Turns {
+			questions = append(questions, turn.Question)
+		}
+		assert.Equal(t, tt.wantQuestions, questions, "messageCount %d", tt.messageCount)
+	}
+}
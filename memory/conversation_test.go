@@ -5,7 +5,9 @@ import (
 	"testing"
 
 	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/ollama/ollama/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConversationManager_LoadSession(t *testing.T) {
@@ -38,14 +40,25 @@ func TestConversation_AddMessages(t *testing.T) {
 		assert.Equal(t, "Hi there!", conversation.Messages[0].Content)
 	})
 
+	t.Run("AddToolCalls", func(t *testing.T) {
+		conversation := &Conversation{}
+		calls := []api.ToolCall{{Function: api.ToolCallFunction{Name: "search", Arguments: api.ToolCallFunctionArguments{"query": "go"}}}}
+		conversation.AddToolCalls(calls)
+
+		assert.Len(t, conversation.Messages, 1)
+		assert.Equal(t, "assistant", conversation.Messages[0].Role)
+		assert.Empty(t, conversation.Messages[0].Content)
+		assert.Equal(t, calls, conversation.Messages[0].ToolCalls)
+	})
+
 	t.Run("AddToolResult", func(t *testing.T) {
 		conversation := &Conversation{}
-		conversation.AddToolResult("Tool executed successfully")
+		conversation.AddToolResult("search", "Tool executed successfully")
 
 		assert.Len(t, conversation.Messages, 1)
-		assert.Equal(t, "user", conversation.Messages[0].Role)
+		assert.Equal(t, "tool", conversation.Messages[0].Role)
+		assert.Equal(t, "search", conversation.Messages[0].ToolName)
 		assert.Equal(t, "Tool executed successfully", conversation.Messages[0].Content)
-		assert.True(t, conversation.Messages[0].IsToolResult)
 	})
 }
 
@@ -129,6 +142,24 @@ func TestConversationManager_trimForSession(t *testing.T) {
 				{Role: "user", Content: "How are you?"},
 			},
 		},
+		{
+			name:    "tool calls and results stay with their user message",
+			maxMsgs: 1,
+			input: []llm.Message{
+				{Role: "user", Content: "Hello"},
+				{Role: "assistant", Content: "Hi!"},
+				{Role: "user", Content: "Search Go"},
+				{Role: "assistant", ToolCalls: []api.ToolCall{{Function: api.ToolCallFunction{Name: "search"}}}},
+				{Role: "tool", Content: "Go is a language", ToolName: "search"},
+				{Role: "assistant", Content: "Go is a language"},
+			},
+			expected: []llm.Message{
+				{Role: "user", Content: "Search Go"},
+				{Role: "assistant", ToolCalls: []api.ToolCall{{Function: api.ToolCallFunction{Name: "search"}}}},
+				{Role: "tool", Content: "Go is a language", ToolName: "search"},
+				{Role: "assistant", Content: "Go is a language"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -152,3 +183,19 @@ func TestConversationManager_SaveSession(t *testing.T) {
 		assert.NoError(t, err) // Should not error with nil collection
 	})
 }
+
+func TestConversationManager_SaveSessionTrimsTurns(t *testing.T) {
+	cm := NewConversationManagerWithStore(NewInMemoryStore(0), 2)
+	conversation := &Conversation{ID: "s1"}
+	for _, question := range []string{"First", "Second", "Third"} {
+		conversation.AddUserMessage(question)
+		conversation.AddAssistantMessage("Answer")
+		conversation.AddTurn(TurnRecord{Question: question, Answer: "Answer"})
+	}
+
+	require.NoError(t, cm.SaveSession(context.Background(), conversation))
+
+	require.Len(t, conversation.Turns, 2, "Turn records are kept for the retained turns")
+	assert.Equal(t, "Second", conversation.Turns[0].Question)
+	assert.Equal(t, "Second", conversation.Messages[0].Content)
+}
@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FileStore is a Store saving each conversation as a JSON file in a directory, giving CLI tools
+// persistent sessions without a database. It is meant for one process; concurrent processes
+// sharing a directory may overwrite each other's saves.
+type FileStore struct {
+	mu  sync.RWMutex
+	dir string
+}
+
+// NewFileStore creates a store in dir, creating the directory if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create session directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) Get(ctx context.Context, id string) (*Conversation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	conversation, err := readConversation(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrSessionNotFound
+	}
+	return conversation, err
+}
+
+func (s *FileStore) Save(ctx context.Context, conversation Conversation) error {
+	data, err := json.Marshal(conversation)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Write to a temporary file and rename it, so a crash never leaves a truncated session
+	tmp, err := os.CreateTemp(s.dir, ".session-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(conversation.ID))
+}
+
+func (s *FileStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) List(ctx context.Context, userID string, limit, offset int) ([]Conversation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var conversations []Conversation
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		conversation, err := readConversation(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		conversations = append(conversations, *conversation)
+	}
+	return listPage(conversations, userID, limit, offset), nil
+}
+
+// path returns the file of a session. IDs are encoded so any ID is a valid file name.
+func (s *FileStore) path(id string) string {
+	return filepath.Join(s.dir, base64.RawURLEncoding.EncodeToString([]byte(id))+".json")
+}
+
+func readConversation(path string) (*Conversation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var conversation Conversation
+	if err := json.Unmarshal(data, &conversation); err != nil {
+		return nil, fmt.Errorf("decode session %s: %w", filepath.Base(path), err)
+	}
+	return &conversation, nil
+}
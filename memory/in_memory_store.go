@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// InMemoryStore is a Store keeping conversations in process memory, for tests and short-lived
+// programs. Conversations not saved for longer than the TTL are evicted.
+type InMemoryStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[string]inMemoryEntry
+}
+
+type inMemoryEntry struct {
+	conversation Conversation
+	savedAt      time.Time
+}
+
+// NewInMemoryStore creates an empty store. A ttl of zero keeps conversations until deleted.
+func NewInMemoryStore(ttl time.Duration) *InMemoryStore {
+	return &InMemoryStore{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]inMemoryEntry{},
+	}
+}
+
+func (s *InMemoryStore) Get(ctx context.Context, id string) (*Conversation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[id]
+	if !ok || s.expired(entry) {
+		return nil, ErrSessionNotFound
+	}
+	conversation := cloneConversation(entry.conversation)
+	return &conversation, nil
+}
+
+func (s *InMemoryStore) Save(ctx context.Context, conversation Conversation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictExpired()
+	s.entries[conversation.ID] = inMemoryEntry{conversation: cloneConversation(conversation), savedAt: s.now()}
+	return nil
+}
+
+func (s *InMemoryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, id)
+	return nil
+}
+
+func (s *InMemoryStore) List(ctx context.Context, userID string, limit, offset int) ([]Conversation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictExpired()
+	conversations := make([]Conversation, 0, len(s.entries))
+	for _, entry := range s.entries {
+		conversations = append(conversations, cloneConversation(entry.conversation))
+	}
+	return listPage(conversations, userID, limit, offset), nil
+}
+
+func (s *InMemoryStore) expired(entry inMemoryEntry) bool {
+	return s.ttl > 0 && s.now().Sub(entry.savedAt) > s.ttl
+}
+
+// evictExpired removes expired conversations. The caller must hold s.mu.
+func (s *InMemoryStore) evictExpired() {
+	for id, entry := range s.entries {
+		if s.expired(entry) {
+			delete(s.entries, id)
+		}
+	}
+}
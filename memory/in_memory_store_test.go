@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryStoreTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+	store := NewInMemoryStore(time.Minute)
+	store.now = func() time.Time { return now }
+
+	require.NoError(t, store.Save(ctx, Conversation{ID: "old"}))
+	now = now.Add(45 * time.Second)
+	require.NoError(t, store.Save(ctx, Conversation{ID: "new"}))
+
+	now = now.Add(30 * time.Second)
+	_, err := store.Get(ctx, "old")
+	assert.ErrorIs(t, err, ErrSessionNotFound, "Sessions expire a TTL after their last save")
+	_, err = store.Get(ctx, "new")
+	assert.NoError(t, err)
+
+	sessions, err := store.List(ctx, "", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "new", sessions[0].ID)
+	assert.NotContains(t, store.entries, "old", "Expired sessions are evicted")
+}
+
+func TestInMemoryStoreCopiesConversations(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryStore(0)
+
+	conversation := Conversation{ID: "s1"}
+	conversation.AddUserMessage("Hello")
+	require.NoError(t, store.Save(ctx, conversation))
+
+	loaded, err := store.Get(ctx, "s1")
+	require.NoError(t, err)
+	loaded.Messages[0].Content = "Changed"
+
+	reloaded, err := store.Get(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", reloaded.Messages[0].Content)
+}
+
+func TestInMemoryStoreConcurrentTurns(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryStore(0)
+
+	conversation := Conversation{ID: "s1"}
+	for range 3 {
+		conversation.AddTurn(TurnRecord{Question: "Hello", ToolCalls: []ToolCallRecord{{Name: "search"}}})
+	}
+	require.NoError(t, store.Save(ctx, conversation))
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			loaded, err := store.Get(ctx, "s1")
+			if !assert.NoError(t, err) {
+				return
+			}
+			loaded.AddTurn(TurnRecord{Question: fmt.Sprintf("Question %d", i)})
+			loaded.Turns[0].ToolCalls[0].Name = fmt.Sprintf("tool %d", i)
+			assert.NoError(t, store.Save(ctx, *loaded))
+		}()
+	}
+	wg.Wait()
+
+	// Loaded turns don't share tool calls with the stored session
+	loaded, err := store.Get(ctx, "s1")
+	require.NoError(t, err)
+	loaded.Turns[1].ToolCalls[0].Name = "changed"
+
+	reloaded, err := store.Get(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, "search", reloaded.Turns[1].ToolCalls[0].Name)
+}
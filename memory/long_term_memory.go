@@ -0,0 +1,246 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/SaiNageswarS/agent-boot/prompts"
+)
+
+const (
+	// duplicateFactSimilarity is the cosine similarity above which a new fact is considered a
+	// restatement of a known one and not stored again.
+	duplicateFactSimilarity = 0.95
+	// maxKnownFacts caps the known facts given to the model when extracting new ones.
+	maxKnownFacts       = 50
+	extractionMaxTokens = 500
+)
+
+// Fact is a durable piece of information about a user, remembered across sessions.
+type Fact struct {
+	ID        string
+	UserID    string
+	Content   string
+	Embedding []float32
+	CreatedAt int64 // unix milliseconds
+}
+
+// ScoredFact is a Fact found by a search, with its cosine similarity to the query.
+type ScoredFact struct {
+	Fact
+	Score float32
+}
+
+// VectorIndex is an in-process index of facts, searched by cosine similarity within the facts of
+// a user. It is safe for concurrent use.
+type VectorIndex struct {
+	mu    sync.RWMutex
+	facts map[string][]Fact // by user ID
+}
+
+func NewVectorIndex() *VectorIndex {
+	return &VectorIndex{facts: map[string][]Fact{}}
+}
+
+// Add indexes fact under its UserID.
+func (ix *VectorIndex) Add(fact Fact) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.facts[fact.UserID] = append(ix.facts[fact.UserID], fact)
+}
+
+// Search returns up to k facts of userID most similar to embedding, best first.
+func (ix *VectorIndex) Search(userID string, embedding []float32, k int) []ScoredFact {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.search(userID, embedding, k)
+}
+
+// addUnlessSimilar indexes fact unless a fact of its user has a similarity of at least threshold,
+// reporting whether it was added. The check and the insertion are atomic.
+func (ix *VectorIndex) addUnlessSimilar(fact Fact, threshold float32) bool {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	if similar := ix.search(fact.UserID, fact.Embedding, 1); len(similar) > 0 && similar[0].Score >= threshold {
+		return false
+	}
+	ix.facts[fact.UserID] = append(ix.facts[fact.UserID], fact)
+	return true
+}
+
+// search is Search without locking. The caller must hold ix.mu.
+func (ix *VectorIndex) search(userID string, embedding []float32, k int) []ScoredFact {
+	scored := make([]ScoredFact, 0, len(ix.facts[userID]))
+	for _, fact := range ix.facts[userID] {
+		scored = append(scored, ScoredFact{Fact: fact, Score: cosineSimilarity(embedding, fact.Embedding)})
+	}
+	slices.SortStableFunc(scored, func(a, b ScoredFact) int { return cmp.Compare(b.Score, a.Score) })
+	return scored[:min(k, len(scored))]
+}
+
+// Facts returns the facts of userID, oldest first.
+func (ix *VectorIndex) Facts(userID string) []Fact {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return slices.Clone(ix.facts[userID])
+}
+
+// Forget removes all facts of userID.
+func (ix *VectorIndex) Forget(userID string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	delete(ix.facts, userID)
+}
+
+// LongTermMemory remembers facts about users across sessions. Facts are extracted from completed
+// turns by a model and recalled by their similarity to new questions.
+type LongTermMemory struct {
+	embedder llm.Embedder
+	index    *VectorIndex
+	prompts  *prompts.Registry
+}
+
+// NewLongTermMemory creates a memory embedding facts with embedder into an in-process index.
+func NewLongTermMemory(embedder llm.Embedder) *LongTermMemory {
+	return &LongTermMemory{embedder: embedder, index: NewVectorIndex()}
+}
+
+// Index returns the index holding the remembered facts.
+func (m *LongTermMemory) Index() *VectorIndex {
+	return m.index
+}
+
+// SetPrompts renders the fact extraction prompts with registry instead of the embedded templates.
+func (m *LongTermMemory) SetPrompts(registry *prompts.Registry) {
+	m.prompts = registry
+}
+
+// Remember stores facts about userID, skipping those restating a fact already known.
+func (m *LongTermMemory) Remember(ctx context.Context, userID string, facts []string) error {
+	if len(facts) == 0 {
+		return nil
+	}
+
+	embeddings, err := m.embedder.Embed(ctx, facts)
+	if err != nil {
+		return fmt.Errorf("embed facts: %w", err)
+	}
+	if len(embeddings) != len(facts) {
+		return fmt.Errorf("embedder returned %d embeddings for %d facts", len(embeddings), len(facts))
+	}
+
+	for i, content := range facts {
+		id, err := randomID()
+		if err != nil {
+			return err
+		}
+		m.index.addUnlessSimilar(Fact{
+			ID:        id,
+			UserID:    userID,
+			Content:   content,
+			Embedding: embeddings[i],
+			CreatedAt: time.Now().UnixMilli(),
+		}, duplicateFactSimilarity)
+	}
+	return nil
+}
+
+// Recall returns up to k facts about userID relevant to query, most relevant first.
+func (m *LongTermMemory) Recall(ctx context.Context, userID, query string, k int) ([]Fact, error) {
+	if k <= 0 || len(m.index.Facts(userID)) == 0 {
+		return nil, nil
+	}
+
+	embeddings, err := m.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+	if len(embeddings) != 1 {
+		return nil, fmt.Errorf("embedder returned %d embeddings for the query", len(embeddings))
+	}
+
+	var facts []Fact
+	for _, scored := range m.index.Search(userID, embeddings[0], k) {
+		if scored.Score > 0 {
+			facts = append(facts, scored.Fact)
+		}
+	}
+	return facts, nil
+}
+
+// Learn asks model for the durable facts about userID in a completed turn, from its user message
+// to the answer, and remembers the new ones. It returns the facts extracted. opts are added to
+// those of the model call, e.g. llm.WithUsageCallback.
+func (m *LongTermMemory) Learn(ctx context.Context, model llm.LLMClient, userID string, turn []llm.Message, opts ...llm.LLMOption) ([]string, error) {
+	known := m.index.Facts(userID)
+	knownFacts := make([]string, 0, min(len(known), maxKnownFacts))
+	for _, fact := range known[max(len(known)-maxKnownFacts, 0):] {
+		knownFacts = append(knownFacts, fact.Content)
+	}
+
+	systemPrompt, userPrompt, err := m.prompts.RenderMemoryExtractionPrompt(knownFacts, transcript(turn), prompts.Vars{})
+	if err != nil {
+		return nil, err
+	}
+
+	var response strings.Builder
+	err = model.GenerateInference(ctx,
+		[]llm.Message{{Role: "user", Content: userPrompt}},
+		func(chunk string) error {
+			response.WriteString(chunk)
+			return nil
+		},
+		append([]llm.LLMOption{
+			llm.WithSystemPrompt(systemPrompt),
+			llm.WithMaxTokens(extractionMaxTokens),
+			llm.WithTemperature(0),
+		}, opts...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	facts := parseFacts(response.String())
+	if err := m.Remember(ctx, userID, facts); err != nil {
+		return nil, err
+	}
+	return facts, nil
+}
+
+// parseFacts reads the list of facts in a model response, one per line with optional bullets.
+func parseFacts(response string) []string {
+	var facts []string
+	for _, line := range strings.Split(response, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*•"))
+		if line == "" || strings.EqualFold(strings.Trim(line, "\"."), "none") {
+			continue
+		}
+		facts = append(facts, line)
+	}
+	return facts
+}
+
+func cosineSimilarity(a, b []float32) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return float32(dot / math.Sqrt(normA*normB))
+}
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLongTermMemory_RememberAndRecall(t *testing.T) {
+	ctx := context.Background()
+	mem := NewLongTermMemory(llm.NewHashEmbedder(512))
+
+	require.NoError(t, mem.Remember(ctx, "alice", []string{
+		"Alice lives in Berlin",
+		"Alice prefers answers in metric units",
+		"alice lives in berlin.",
+	}))
+	require.NoError(t, mem.Remember(ctx, "bob", []string{"Bob lives in Paris"}))
+
+	assert.Len(t, mem.Index().Facts("alice"), 2, "Restated facts are not stored again")
+
+	facts, err := mem.Recall(ctx, "alice", "Where does Alice live?", 1)
+	require.NoError(t, err)
+	require.Len(t, facts, 1)
+	assert.Equal(t, "Alice lives in Berlin", facts[0].Content)
+
+	facts, err = mem.Recall(ctx, "carol", "Where does Carol live?", 3)
+	require.NoError(t, err)
+	assert.Empty(t, facts, "Facts are scoped to their user")
+
+	mem.Index().Forget("alice")
+	assert.Empty(t, mem.Index().Facts("alice"))
+	assert.Len(t, mem.Index().Facts("bob"), 1)
+}
+
+func TestLongTermMemory_ConcurrentRememberStoresFactOnce(t *testing.T) {
+	mem := NewLongTermMemory(llm.NewHashEmbedder(512))
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, mem.Remember(context.Background(), "alice", []string{"Alice lives in Berlin"}))
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, mem.Index().Facts("alice"), 1)
+}
+
+func TestLongTermMemory_Learn(t *testing.T) {
+	ctx := context.Background()
+	mem := NewLongTermMemory(llm.NewHashEmbedder(512))
+	require.NoError(t, mem.Remember(ctx, "alice", []string{"Alice lives in Berlin"}))
+
+	model := &summaryModel{summaries: []string{"- Alice is vegetarian\n- Alice is planning a trip to Rome\n"}}
+	turn := []llm.Message{
+		{Role: "user", Content: "I'm vegetarian, any restaurant tips for my Rome trip?"},
+		{Role: "assistant", Content: "Try Ops! near the Spanish Steps."},
+	}
+
+	facts, err := mem.Learn(ctx, model, "alice", turn)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Alice is vegetarian", "Alice is planning a trip to Rome"}, facts)
+	assert.Len(t, mem.Index().Facts("alice"), 3)
+
+	require.Len(t, model.prompts, 1)
+	assert.Contains(t, model.prompts[0], "- Alice lives in Berlin", "Known facts are given to the model")
+	assert.Contains(t, model.prompts[0], "User: I'm vegetarian")
+
+	model.summaries = []string{"NONE"}
+	facts, err = mem.Learn(ctx, model, "alice", turn)
+	require.NoError(t, err)
+	assert.Empty(t, facts)
+}
+
+func TestParseFacts(t *testing.T) {
+	assert.Equal(t, []string{"Likes tea", "Works at night", "Has a cat"},
+		parseFacts("- Likes tea\n* Works at night\n\n  •  Has a cat  \n"))
+	assert.Empty(t, parseFacts("NONE"))
+	assert.Empty(t, parseFacts("None."))
+}
+
+func TestVectorIndex_Search(t *testing.T) {
+	ix := NewVectorIndex()
+	ix.Add(Fact{ID: "1", UserID: "alice", Embedding: []float32{1, 0}})
+	ix.Add(Fact{ID: "2", UserID: "alice", Embedding: []float32{0.6, 0.8}})
+	ix.Add(Fact{ID: "3", UserID: "alice", Embedding: []float32{0, 1}})
+
+	results := ix.Search("alice", []float32{0, 2}, 2)
+	require.Len(t, results, 2)
+	assert.Equal(t, "3", results[0].ID)
+	assert.InDelta(t, 1, results[0].Score, 1e-6)
+	assert.Equal(t, "2", results[1].ID)
+	assert.InDelta(t, 0.8, results[1].Score, 1e-6)
+}
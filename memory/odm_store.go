@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SaiNageswarS/go-api-boot/odm"
+	"github.com/SaiNageswarS/go-collection-boot/async"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// OdmStore is a Store backed by a go-api-boot odm collection, typically MongoDB.
+type OdmStore struct {
+	collection odm.OdmCollectionInterface[Conversation]
+}
+
+// NewOdmStore creates a store saving conversations in collection.
+func NewOdmStore(collection odm.OdmCollectionInterface[Conversation]) *OdmStore {
+	return &OdmStore{collection: collection}
+}
+
+func (s *OdmStore) Get(ctx context.Context, id string) (*Conversation, error) {
+	conversation, err := async.Await(s.collection.FindOneByID(ctx, id))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrSessionNotFound
+	}
+	return conversation, err
+}
+
+func (s *OdmStore) Save(ctx context.Context, conversation Conversation) error {
+	_, err := async.Await(s.collection.Save(ctx, conversation))
+	return err
+}
+
+func (s *OdmStore) Delete(ctx context.Context, id string) error {
+	_, err := async.Await(s.collection.DeleteByID(ctx, id))
+	return err
+}
+
+func (s *OdmStore) List(ctx context.Context, userID string, limit, offset int) ([]Conversation, error) {
+	return async.Await(s.collection.Find(ctx, userFilter(userID),
+		bson.D{{Key: "updatedAt", Value: -1}, {Key: "_id", Value: 1}},
+		int64(limit), int64(offset)))
+}
+
+// userFilter matches the sessions of userID; an empty userID matches sessions saved without one.
+func userFilter(userID string) bson.M {
+	if userID == "" {
+		return bson.M{"userId": nil}
+	}
+	return bson.M{"userId": userID}
+}
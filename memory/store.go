@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+)
+
+// Store persists the conversations of a ConversationManager. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	// Get returns the conversation with the given ID, or ErrSessionNotFound.
+	Get(ctx context.Context, id string) (*Conversation, error)
+	// Save creates or replaces a conversation.
+	Save(ctx context.Context, conversation Conversation) error
+	// Delete removes a conversation. Deleting a missing conversation is not an error.
+	Delete(ctx context.Context, id string) error
+	// List returns up to limit conversations of userID after skipping offset of them, most
+	// recently updated first and then by ID. An empty userID lists conversations without a user.
+	List(ctx context.Context, userID string, limit, offset int) ([]Conversation, error)
+}
+
+// listPage filters conversations to those of userID and returns the requested page of them in
+// Store.List order. It reorders conversations.
+func listPage(conversations []Conversation, userID string, limit, offset int) []Conversation {
+	conversations = slices.DeleteFunc(conversations, func(c Conversation) bool { return c.UserID != userID })
+	slices.SortFunc(conversations, func(a, b Conversation) int {
+		if a.UpdatedAt != b.UpdatedAt {
+			return cmp.Compare(b.UpdatedAt, a.UpdatedAt)
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	conversations = conversations[min(offset, len(conversations)):]
+	if limit > 0 && limit < len(conversations) {
+		conversations = conversations[:limit]
+	}
+	return conversations
+}
+
+// cloneConversation copies the slices of conversation, so stores never share them with callers.
+func cloneConversation(conversation Conversation) Conversation {
+	conversation.Messages = slices.Clone(conversation.Messages)
+	conversation.Turns = cloneTurns(conversation.Turns)
+	return conversation
+}
@@ -0,0 +1,86 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SaiNageswarS/agent-boot/internal/memorytest"
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/SaiNageswarS/agent-boot/memory"
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) memory.Store{
+		"odm":       func(t *testing.T) memory.Store { return memory.NewOdmStore(memorytest.NewCollection()) },
+		"in memory": func(t *testing.T) memory.Store { return memory.NewInMemoryStore(0) },
+		"file": func(t *testing.T) memory.Store {
+			store, err := memory.NewFileStore(t.TempDir())
+			require.NoError(t, err)
+			return store
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			_, err := store.Get(ctx, "missing")
+			assert.ErrorIs(t, err, memory.ErrSessionNotFound)
+
+			conversation := memory.Conversation{ID: "a/1", UserID: "alice", UpdatedAt: 1, Messages: []llm.Message{
+				{Role: "user", Content: "What is 2+2?"},
+				{Role: "assistant", ToolCalls: []api.ToolCall{{Function: api.ToolCallFunction{Name: "add", Arguments: api.ToolCallFunctionArguments{"a": 2.0}}}}},
+				{Role: "tool", ToolName: "add", Content: "4"},
+			}}
+			require.NoError(t, store.Save(ctx, conversation))
+			require.NoError(t, store.Save(ctx, memory.Conversation{ID: "a/2", UserID: "alice", UpdatedAt: 3}))
+			require.NoError(t, store.Save(ctx, memory.Conversation{ID: "a/3", UserID: "alice", UpdatedAt: 2}))
+			require.NoError(t, store.Save(ctx, memory.Conversation{ID: "b/1", UserID: "bob", UpdatedAt: 4}))
+			require.NoError(t, store.Save(ctx, memory.Conversation{ID: "anonymous", UpdatedAt: 5}))
+
+			saved, err := store.Get(ctx, "a/1")
+			require.NoError(t, err)
+			assert.Equal(t, conversation, *saved)
+
+			sessions, err := store.List(ctx, "alice", 2, 0)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"a/2", "a/3"}, sessionIDs(sessions))
+
+			sessions, err = store.List(ctx, "alice", 2, 2)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"a/1"}, sessionIDs(sessions))
+
+			sessions, err = store.List(ctx, "", 10, 0)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"anonymous"}, sessionIDs(sessions))
+
+			require.NoError(t, store.Delete(ctx, "a/1"))
+			require.NoError(t, store.Delete(ctx, "a/1"), "Deleting a missing session is not an error")
+			_, err = store.Get(ctx, "a/1")
+			assert.ErrorIs(t, err, memory.ErrSessionNotFound)
+		})
+	}
+}
+
+func TestFileStorePersistsAcrossInstances(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := memory.NewFileStore(dir)
+	require.NoError(t, err)
+	cm := memory.NewConversationManagerWithStore(store, 10)
+
+	conversation := cm.LoadSession(ctx, "s1")
+	conversation.AddUserMessage("Hello")
+	conversation.AddAssistantMessage("Hi there!")
+	require.NoError(t, cm.SaveSession(ctx, conversation))
+
+	reopened, err := memory.NewFileStore(dir)
+	require.NoError(t, err)
+	loaded := memory.NewConversationManagerWithStore(reopened, 10).LoadSession(ctx, "s1")
+	assert.Equal(t, conversation.Messages, loaded.Messages)
+}
@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/SaiNageswarS/agent-boot/prompts"
+	"github.com/SaiNageswarS/go-api-boot/logger"
+	"go.uber.org/zap"
+)
+
+// summaryMessagePrefix introduces Conversation.Summary in ContextMessages.
+const summaryMessagePrefix = "Summary of our earlier conversation:\n\n"
+
+const (
+	summaryMaxTokens = 1000
+	// transcriptToolResultChars caps each tool result in the transcript being summarized.
+	transcriptToolResultChars = 2000
+)
+
+// summarizer folds the oldest turns of long sessions into Conversation.Summary.
+type summarizer struct {
+	model     llm.LLMClient
+	threshold int
+	keepTurns int
+}
+
+// SetSummarizer makes SaveSession fold the oldest turns of sessions with more than threshold user
+// turns into Conversation.Summary using model, keeping the latest keepTurns turns as messages.
+// Each fold updates the previous summary, so sessions keep their key facts without growing. A
+// keepTurns not between 1 and threshold keeps half the threshold; a threshold of zero or less
+// disables summarization.
+func (cm *ConversationManager) SetSummarizer(model llm.LLMClient, threshold, keepTurns int) {
+	if model == nil || threshold <= 0 {
+		cm.summarizer = nil
+		return
+	}
+	if keepTurns <= 0 || keepTurns >= threshold {
+		keepTurns = max(threshold/2, 1)
+	}
+	cm.summarizer = &summarizer{model: model, threshold: threshold, keepTurns: keepTurns}
+}
+
+// Summarize folds the oldest turns of conversation into its Summary like SaveSession does, so the
+// summarization call can be made with opts, e.g. llm.WithUsageCallback. It does nothing without a
+// summarizer or while the conversation is short enough.
+func (cm *ConversationManager) Summarize(ctx context.Context, conversation *Conversation, opts ...llm.LLMOption) error {
+	if cm.summarizer == nil {
+		return nil
+	}
+	return cm.summarizer.summarize(ctx, cm.prompts, conversation, opts...)
+}
+
+// summarize folds the turns of conversation before its latest keepTurns into its Summary once it
+// has more than threshold turns.
+func (s *summarizer) summarize(ctx context.Context, registry *prompts.Registry, conversation *Conversation, opts ...llm.LLMOption) error {
+	var turnStarts []int
+	for i, msg := range conversation.Messages {
+		if isUserMessage(msg) {
+			turnStarts = append(turnStarts, i)
+		}
+	}
+	if len(turnStarts) <= s.threshold {
+		return nil
+	}
+
+	split := turnStarts[len(turnStarts)-s.keepTurns]
+	systemPrompt, userPrompt, err := registry.RenderConversationSummaryPrompt(
+		conversation.Summary, transcript(conversation.Messages[:split]), prompts.Vars{})
+	if err != nil {
+		return err
+	}
+
+	var summary strings.Builder
+	err = s.model.GenerateInference(ctx,
+		[]llm.Message{{Role: "user", Content: userPrompt}},
+		func(chunk string) error {
+			summary.WriteString(chunk)
+			return nil
+		},
+		append([]llm.LLMOption{
+			llm.WithSystemPrompt(systemPrompt),
+			llm.WithMaxTokens(summaryMaxTokens),
+			llm.WithTemperature(0),
+		}, opts...)...,
+	)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(summary.String()) == "" {
+		return errors.New("summary is empty")
+	}
+
+	logger.Info("Summarized conversation history",
+		zap.String("session_id", conversation.ID),
+		zap.Int("folded_messages", split))
+
+	conversation.Summary = strings.TrimSpace(summary.String())
+	conversation.Messages = slices.Clone(conversation.Messages[split:])
+	return nil
+}
+
+// transcript renders messages as plain text for summarization.
+func transcript(msgs []llm.Message) string {
+	var sb strings.Builder
+	for _, msg := range msgs {
+		switch {
+		case msg.Role == "tool" || msg.IsToolResult:
+			content := msg.Content
+			if len(content) > transcriptToolResultChars {
+				content = strings.ToValidUTF8(content[:transcriptToolResultChars], "") + " [...]"
+			}
+			fmt.Fprintf(&sb, "Tool %s returned: %s\n", msg.ToolName, content)
+		case msg.Role == "user":
+			fmt.Fprintf(&sb, "User: %s\n", msg.Content)
+		default:
+			if msg.Content != "" {
+				fmt.Fprintf(&sb, "Assistant: %s\n", msg.Content)
+			}
+			for _, call := range msg.ToolCalls {
+				args, _ := json.Marshal(call.Function.Arguments)
+				fmt.Fprintf(&sb, "Assistant called tool %s with %s\n", call.Function.Name, args)
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// summaryModel answers every call with the next of its summaries and records the prompts.
+type summaryModel struct {
+	summaries []string
+	err       error
+	prompts   []string
+}
+
+func (m *summaryModel) GenerateInference(ctx context.Context, messages []llm.Message, callback func(chunk string) error, opts ...llm.LLMOption) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.prompts = append(m.prompts, messages[len(messages)-1].Content)
+	summary := m.summaries[0]
+	m.summaries = m.summaries[1:]
+	return callback(summary)
+}
+
+func (m *summaryModel) GenerateInferenceWithTools(ctx context.Context, messages []llm.Message, contentCallback func(chunk string) error, toolCallback func(toolCalls []api.ToolCall) error, opts ...llm.LLMOption) error {
+	return m.GenerateInference(ctx, messages, contentCallback, opts...)
+}
+
+func (m *summaryModel) Capabilities() llm.Capability { return 0 }
+
+func (m *summaryModel) GetModel() string { return "summary-model" }
+
+// addTurns adds question/answer turns numbered from first.
+func addTurns(conversation *Conversation, first, count int) {
+	for i := first; i < first+count; i++ {
+		conversation.AddUserMessage(fmt.Sprintf("Question %d", i))
+		conversation.AddAssistantMessage(fmt.Sprintf("Answer %d", i))
+	}
+}
+
+func TestConversationManager_Summarization(t *testing.T) {
+	ctx := context.Background()
+	model := &summaryModel{summaries: []string{"First summary", "Second summary"}}
+	store := NewInMemoryStore(0)
+	cm := NewConversationManagerWithStore(store, 100)
+	cm.SetSummarizer(model, 4, 2)
+
+	conversation := cm.LoadSession(ctx, "s1")
+	addTurns(conversation, 1, 4)
+	require.NoError(t, cm.SaveSession(ctx, conversation))
+	assert.Empty(t, model.prompts, "Sessions within the threshold are not summarized")
+
+	addTurns(conversation, 5, 1)
+	require.NoError(t, cm.SaveSession(ctx, conversation))
+
+	saved, err := store.Get(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, "First summary", saved.Summary)
+	require.Len(t, saved.Messages, 4, "The latest two turns are kept")
+	assert.Equal(t, "Question 4", saved.Messages[0].Content)
+	require.Len(t, model.prompts, 1)
+	assert.Contains(t, model.prompts[0], "User: Question 3")
+	assert.NotContains(t, model.prompts[0], "Question 4")
+
+	// The next fold updates the previous summary with the turns that followed
+	addTurns(saved, 6, 3)
+	require.NoError(t, cm.SaveSession(ctx, saved))
+
+	assert.Equal(t, "Second summary", saved.Summary)
+	assert.Equal(t, "Question 7", saved.Messages[0].Content)
+	require.Len(t, model.prompts, 2)
+	assert.Contains(t, model.prompts[1], "First summary")
+	assert.Contains(t, model.prompts[1], "User: Question 6")
+
+	msgs := saved.ContextMessages()
+	require.Len(t, msgs, 5)
+	assert.Equal(t, summaryMessagePrefix+"Second summary", msgs[0].Content)
+}
+
+func TestConversationManager_SummarizationFailure(t *testing.T) {
+	ctx := context.Background()
+	cm := NewConversationManagerWithStore(NewInMemoryStore(0), 3)
+	cm.SetSummarizer(&summaryModel{err: errors.New("model unavailable")}, 2, 1)
+
+	conversation := cm.LoadSession(ctx, "s1")
+	addTurns(conversation, 1, 4)
+
+	// The session is still saved, trimmed as without a summarizer
+	require.NoError(t, cm.SaveSession(ctx, conversation))
+	assert.Empty(t, conversation.Summary)
+	assert.Equal(t, "Question 2", conversation.Messages[0].Content)
+}
+
+func TestSetSummarizer(t *testing.T) {
+	cm := NewConversationManager(nil, 10)
+	model := &summaryModel{}
+
+	cm.SetSummarizer(model, 10, 0)
+	require.NotNil(t, cm.summarizer)
+	assert.Equal(t, 5, cm.summarizer.keepTurns)
+
+	cm.SetSummarizer(model, 1, 1)
+	assert.Equal(t, 1, cm.summarizer.keepTurns)
+
+	cm.SetSummarizer(model, 0, 0)
+	assert.Nil(t, cm.summarizer)
+}
+
+func TestTranscript(t *testing.T) {
+	msgs := []llm.Message{
+		{Role: "user", Content: "Where is order 1234?"},
+		{Role: "assistant", ToolCalls: []api.ToolCall{{Function: api.ToolCallFunction{Name: "track", Arguments: api.ToolCallFunctionArguments{"order": "1234"}}}}},
+		{Role: "tool", ToolName: "track", Content: "In transit"},
+		{Role: "assistant", Content: "It is in transit."},
+	}
+
+	assert.Equal(t, "User: Where is order 1234?\n"+
+		"Assistant called tool track with {\"order\":\"1234\"}\n"+
+		"Tool track returned: In transit\n"+
+		"Assistant: It is in transit.\n", transcript(msgs))
+}
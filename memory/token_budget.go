@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/SaiNageswarS/agent-boot/llm"
+)
+
+// Tokenizer counts the tokens of a text for a model.
+type Tokenizer func(text string) int
+
+// EstimateTokens is the default Tokenizer. It assumes about four characters per token, which is
+// close for English text with most tokenizers and errs on the high side for code and JSON.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// messageOverheadTokens approximates the tokens providers add around each message for its role.
+const messageOverheadTokens = 4
+
+// Notes replacing tool results shortened to fit a TokenBudget.
+const (
+	truncatedToolResultNote = "\n[... result truncated to fit the context window]"
+	droppedToolResultNote   = "[Result removed to fit the context window]"
+)
+
+// TrimPolicy fits the messages of a conversation into a model's context before an LLM call.
+// Implementations must not modify msgs.
+type TrimPolicy interface {
+	Trim(systemPrompt string, msgs []llm.Message) []llm.Message
+}
+
+// TokenBudget is a TrimPolicy keeping the system prompt and messages within MaxTokens. When they
+// don't fit, it removes content in this order until they do:
+//
+//  1. results of tool calls made before the latest user message, oldest first, are truncated
+//     to ToolResultTokens;
+//  2. earlier exchanges are dropped, oldest first, each user message together with the
+//     assistant and tool messages answering it;
+//  3. results of tool calls made for the latest user message are truncated, oldest first.
+//
+// The system prompt and the latest user message are always kept, even when they alone exceed
+// the budget. Tool results are shortened rather than removed, so every tool call keeps a result.
+type TokenBudget struct {
+	// MaxTokens is the budget of the system prompt and messages. Leave room for the answer and
+	// the tool definitions, which aren't counted.
+	MaxTokens int
+	// ToolResultTokens is how many tokens of a tool result are kept when it is truncated. With
+	// zero, truncated results are replaced by a short note.
+	ToolResultTokens int
+	// Tokenizer counts tokens; EstimateTokens when nil.
+	Tokenizer Tokenizer
+}
+
+// Trim returns msgs fitted into the budget, or msgs itself when they already fit or MaxTokens
+// is not positive.
+func (b TokenBudget) Trim(systemPrompt string, msgs []llm.Message) []llm.Message {
+	if b.MaxTokens <= 0 || len(msgs) == 0 {
+		return msgs
+	}
+
+	tokens := b.countTokens(systemPrompt)
+	for _, msg := range msgs {
+		tokens += b.messageTokens(msg)
+	}
+	if tokens <= b.MaxTokens {
+		return msgs
+	}
+
+	latest := latestUserMessage(msgs)
+	msgs = slices.Clone(msgs)
+
+	// Truncate tool results of earlier exchanges, oldest first
+	for i := 0; i < latest && tokens > b.MaxTokens; i++ {
+		tokens -= b.truncateToolResult(&msgs[i])
+	}
+
+	// Drop earlier exchanges, oldest first, without separating tool calls from their results
+	start := 0
+	for start < latest && tokens > b.MaxTokens {
+		end := start + 1
+		for end < latest && !isUserMessage(msgs[end]) {
+			end++
+		}
+		for _, msg := range msgs[start:end] {
+			tokens -= b.messageTokens(msg)
+		}
+		start = end
+	}
+	msgs = msgs[start:]
+	latest -= start
+
+	// Truncate tool results of the latest exchange, oldest first
+	for i := latest + 1; i < len(msgs) && tokens > b.MaxTokens; i++ {
+		tokens -= b.truncateToolResult(&msgs[i])
+	}
+
+	return msgs
+}
+
+// truncateToolResult shortens msg to ToolResultTokens when it is a longer tool result, returning
+// the tokens saved.
+func (b TokenBudget) truncateToolResult(msg *llm.Message) int {
+	if msg.Role != "tool" && !msg.IsToolResult {
+		return 0
+	}
+
+	before := b.messageTokens(*msg)
+	content := droppedToolResultNote
+	if b.ToolResultTokens > 0 {
+		content = b.truncate(msg.Content, b.ToolResultTokens)
+	}
+	after := b.messageTokens(llm.Message{Role: msg.Role, Content: content})
+	if after >= before {
+		return 0
+	}
+
+	msg.Content = content
+	return before - after
+}
+
+// truncate returns the longest prefix of text that fits maxTokens along with the truncation
+// note, or text itself when it fits.
+func (b TokenBudget) truncate(text string, maxTokens int) string {
+	if b.countTokens(text) <= maxTokens {
+		return text
+	}
+
+	// Binary search the longest prefix that fits
+	runes := []rune(text)
+	lo, hi := 0, len(runes)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if b.countTokens(string(runes[:mid])+truncatedToolResultNote) <= maxTokens {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return string(runes[:lo]) + truncatedToolResultNote
+}
+
+func (b TokenBudget) messageTokens(msg llm.Message) int {
+	tokens := messageOverheadTokens + b.countTokens(msg.Content)
+	for _, call := range msg.ToolCalls {
+		args, _ := json.Marshal(call.Function.Arguments)
+		tokens += b.countTokens(fmt.Sprintf("%s(%s)", call.Function.Name, args))
+	}
+	return tokens
+}
+
+func (b TokenBudget) countTokens(text string) int {
+	if b.Tokenizer == nil {
+		return EstimateTokens(text)
+	}
+	return b.Tokenizer(text)
+}
+
+// latestUserMessage returns the index of the last user message, or 0 without one.
+func latestUserMessage(msgs []llm.Message) int {
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if isUserMessage(msgs[i]) {
+			return i
+		}
+	}
+	return 0
+}
+
+func isUserMessage(msg llm.Message) bool {
+	return msg.Role == "user" && !msg.IsToolResult
+}
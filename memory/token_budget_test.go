@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SaiNageswarS/agent-boot/llm"
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wordTokenizer counts one token per word, keeping the expected budgets easy to follow.
+func wordTokenizer(text string) int {
+	return len(strings.Fields(text))
+}
+
+func toolExchange(question, toolName, result, answer string) []llm.Message {
+	return []llm.Message{
+		{Role: "user", Content: question},
+		{Role: "assistant", ToolCalls: []api.ToolCall{{Function: api.ToolCallFunction{Name: toolName}}}},
+		{Role: "tool", ToolName: toolName, Content: result},
+		{Role: "assistant", Content: answer},
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	assert.Equal(t, 0, EstimateTokens(""))
+	assert.Equal(t, 1, EstimateTokens("abcd"))
+	assert.Equal(t, 2, EstimateTokens("abcde"))
+	assert.Equal(t, 2, EstimateTokens("ééééé"), "Characters are counted, not bytes")
+}
+
+func TestTokenBudget_Trim(t *testing.T) {
+	longResult := strings.Repeat("word ", 100)
+
+	t.Run("fits", func(t *testing.T) {
+		msgs := toolExchange("What is 2+2?", "add", "4", "4")
+		budget := TokenBudget{MaxTokens: 1000}
+
+		assert.Equal(t, msgs, budget.Trim("You are helpful.", msgs))
+	})
+
+	t.Run("truncates earlier tool results first", func(t *testing.T) {
+		msgs := append(toolExchange("Search go", "search", longResult, "Go is a language"),
+			llm.Message{Role: "user", Content: "Thanks"})
+		budget := TokenBudget{MaxTokens: 60, ToolResultTokens: 10, Tokenizer: wordTokenizer}
+
+		trimmed := budget.Trim("", msgs)
+
+		require.Len(t, trimmed, len(msgs), "Truncating the result is enough, no exchange is dropped")
+		assert.True(t, strings.HasSuffix(trimmed[2].Content, truncatedToolResultNote))
+		assert.LessOrEqual(t, wordTokenizer(trimmed[2].Content), 10)
+		assert.Equal(t, longResult, msgs[2].Content, "The input is not modified")
+	})
+
+	t.Run("replaces tool results without ToolResultTokens", func(t *testing.T) {
+		msgs := append(toolExchange("Search go", "search", longResult, "Go is a language"),
+			llm.Message{Role: "user", Content: "Thanks"})
+		budget := TokenBudget{MaxTokens: 60, Tokenizer: wordTokenizer}
+
+		trimmed := budget.Trim("", msgs)
+
+		require.Len(t, trimmed, len(msgs))
+		assert.Equal(t, droppedToolResultNote, trimmed[2].Content)
+	})
+
+	t.Run("drops earlier exchanges whole", func(t *testing.T) {
+		var msgs []llm.Message
+		msgs = append(msgs, toolExchange("First question", "search", "first result", "First answer")...)
+		msgs = append(msgs, toolExchange("Second question", "search", "second result", "Second answer")...)
+		msgs = append(msgs, llm.Message{Role: "user", Content: "Third question"})
+		budget := TokenBudget{MaxTokens: 35, Tokenizer: wordTokenizer}
+
+		trimmed := budget.Trim("You are helpful.", msgs)
+
+		require.Len(t, trimmed, 5)
+		assert.Equal(t, "Second question", trimmed[0].Content)
+		assert.Equal(t, "Third question", trimmed[4].Content)
+	})
+
+	t.Run("truncates the latest tool results last", func(t *testing.T) {
+		msgs := toolExchange("Search go", "search", longResult, "")[:3]
+		budget := TokenBudget{MaxTokens: 30, ToolResultTokens: 10, Tokenizer: wordTokenizer}
+
+		trimmed := budget.Trim("You are helpful.", msgs)
+
+		require.Len(t, trimmed, 3)
+		assert.Equal(t, "Search go", trimmed[0].Content)
+		assert.True(t, strings.HasSuffix(trimmed[2].Content, truncatedToolResultNote))
+	})
+
+	t.Run("keeps the latest user message over budget", func(t *testing.T) {
+		msgs := []llm.Message{
+			{Role: "user", Content: "Hi"},
+			{Role: "assistant", Content: "Hello"},
+			{Role: "user", Content: longResult},
+		}
+		budget := TokenBudget{MaxTokens: 10, Tokenizer: wordTokenizer}
+
+		trimmed := budget.Trim("You are helpful.", msgs)
+
+		require.Len(t, trimmed, 1)
+		assert.Equal(t, longResult, trimmed[0].Content)
+	})
+}
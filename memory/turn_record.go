@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"maps"
+	"slices"
+)
+
+// TurnRecord is the structured record of how one question of a conversation was answered: the
+// tools that ran, their results and latency, the models used and their token usage. Records are
+// kept with the conversation to audit and debug answers after the fact.
+type TurnRecord struct {
+	Question    string           `bson:"question" json:"question"`
+	Answer      string           `bson:"answer" json:"answer"`
+	Model       string           `bson:"model,omitempty" json:"model,omitempty"` // model that generated the answer
+	ToolCalls   []ToolCallRecord `bson:"toolCalls,omitempty" json:"toolCalls,omitempty"`
+	Usage       []UsageRecord    `bson:"usage,omitempty" json:"usage,omitempty"`
+	FinalStatus string           `bson:"finalStatus,omitempty" json:"finalStatus,omitempty"` // why the tool loop ended
+	Error       string           `bson:"error,omitempty" json:"error,omitempty"`             // error generating the answer
+	StartedAt   int64            `bson:"startedAt" json:"startedAt"`                         // unix milliseconds
+	CompletedAt int64            `bson:"completedAt" json:"completedAt"`                     // unix milliseconds
+}
+
+// ToolCallRecord records a tool call made while answering a question.
+type ToolCallRecord struct {
+	Name          string             `bson:"name" json:"name"`
+	Arguments     map[string]any     `bson:"arguments,omitempty" json:"arguments,omitempty"`
+	SelectionTurn int                `bson:"selectionTurn" json:"selectionTurn"` // tool selection turn the call was made in, from 0
+	Results       []ToolResultRecord `bson:"results,omitempty" json:"results,omitempty"`
+	Error         string             `bson:"error,omitempty" json:"error,omitempty"`
+	StartedAt     int64              `bson:"startedAt" json:"startedAt"` // unix milliseconds
+	LatencyMs     int64              `bson:"latencyMs" json:"latencyMs"`
+}
+
+// ToolResultRecord is a result chunk returned by a tool, after summarization when enabled.
+type ToolResultRecord struct {
+	Title       string            `bson:"title,omitempty" json:"title,omitempty"`
+	Attribution string            `bson:"attribution,omitempty" json:"attribution,omitempty"`
+	Sentences   []string          `bson:"sentences,omitempty" json:"sentences,omitempty"`
+	Metadata    map[string]string `bson:"metadata,omitempty" json:"metadata,omitempty"`
+	Error       string            `bson:"error,omitempty" json:"error,omitempty"`
+}
+
+// UsageRecord is the token usage of a model in one role while answering a question.
+type UsageRecord struct {
+	Role             string `bson:"role" json:"role"` // tool selector, mini or big model
+	Model            string `bson:"model" json:"model"`
+	PromptTokens     int    `bson:"promptTokens" json:"promptTokens"`
+	CompletionTokens int    `bson:"completionTokens" json:"completionTokens"`
+}
+
+// cloneTurns deep copies turns, including the tool calls and results of each.
+func cloneTurns(turns []TurnRecord) []TurnRecord {
+	if turns == nil {
+		return nil
+	}
+
+	cloned := make([]TurnRecord, len(turns))
+	for i, turn := range turns {
+		turn.Usage = slices.Clone(turn.Usage)
+		if turn.ToolCalls != nil {
+			calls := make([]ToolCallRecord, len(turn.ToolCalls))
+			for j, call := range turn.ToolCalls {
+				call.Arguments = maps.Clone(call.Arguments)
+				call.Results = slices.Clone(call.Results)
+				calls[j] = call
+			}
+			turn.ToolCalls = calls
+		}
+		cloned[i] = turn
+	}
+	return cloned
+}
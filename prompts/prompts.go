@@ -1,84 +1,48 @@
 package prompts
 
 import (
-	"bytes"
 	"embed"
-	"text/template"
 )
 
 //go:embed templates/*
 var templatesFS embed.FS
 
-// RenderSummarizationPrompt renders the summarization prompt using embedded Go templates
+// RenderSummarizationPrompt renders the summarization prompt using the embedded templates
 func RenderSummarizationPrompt(query, content, toolInputs string) (systemPrompt, userPrompt string, err error) {
-	// Load and parse system prompt template from embedded file
-	systemTemplateContent, err := templatesFS.ReadFile("templates/summarize_context_system.md")
-	if err != nil {
-		return "", "", err
-	}
-
-	systemTmpl, err := template.New("summarize_system").Parse(string(systemTemplateContent))
-	if err != nil {
-		return "", "", err
-	}
-
-	data := struct {
-		Query      string
-		Content    string
-		ToolInputs string
-	}{
-		Query:      query,
-		Content:    content,
-		ToolInputs: toolInputs,
-	}
-
-	var systemBuf bytes.Buffer
-	if err := systemTmpl.Execute(&systemBuf, data); err != nil {
-		return "", "", err
-	}
-
-	// Load and parse user prompt template from embedded file
-	userTemplateContent, err := templatesFS.ReadFile("templates/summarize_context_user.md")
-	if err != nil {
-		return "", "", err
-	}
-
-	userTmpl, err := template.New("summarize_user").Parse(string(userTemplateContent))
-	if err != nil {
-		return "", "", err
-	}
-
-	var userBuf bytes.Buffer
-	if err := userTmpl.Execute(&userBuf, data); err != nil {
-		return "", "", err
-	}
-
-	return systemBuf.String(), userBuf.String(), nil
+	return defaultRegistry.RenderSummarizationPrompt(query, content, toolInputs, Vars{})
 }
 
-// RenderToolSelectionPrompt renders the tool selection system prompt using embedded Go templates
+// RenderToolSelectionPrompt renders the tool selection system prompt using the embedded templates
 func RenderToolSelectionPrompt(turn int) (systemPrompt string, err error) {
-	// Load and parse system prompt template from embedded file
-	systemTemplateContent, err := templatesFS.ReadFile("templates/tool_selection_system.md")
-	if err != nil {
-		return "", err
-	}
+	return defaultRegistry.RenderToolSelectionPrompt(turn, Vars{})
+}
+
+// RenderStructuredAnswerPrompt renders the instructions appended to the system prompt when the
+// answer must conform to a JSON schema. schema is the JSON encoded schema.
+func RenderStructuredAnswerPrompt(schema string) (string, error) {
+	return defaultRegistry.RenderStructuredAnswerPrompt(schema, Vars{})
+}
 
-	systemTmpl, err := template.New("tool_selection_system").Parse(string(systemTemplateContent))
-	if err != nil {
-		return "", err
-	}
+// RenderAnswerRepairPrompt renders the message asking the model to fix an answer that does not
+// conform to the JSON schema, listing the problems found.
+func RenderAnswerRepairPrompt(problems []string) (string, error) {
+	return defaultRegistry.RenderAnswerRepairPrompt(problems, Vars{})
+}
 
-	data := struct {
-		Turn int
-	}{
-		Turn: turn,
-	}
+// RenderConversationSummaryPrompt renders the prompts folding the transcript of older turns of a
+// conversation into its running summary. previousSummary is empty for the first summary.
+func RenderConversationSummaryPrompt(previousSummary, transcript string) (systemPrompt, userPrompt string, err error) {
+	return defaultRegistry.RenderConversationSummaryPrompt(previousSummary, transcript, Vars{})
+}
 
-	var systemBuf bytes.Buffer
-	if err := systemTmpl.Execute(&systemBuf, data); err != nil {
-		return "", err
-	}
+// RenderMemoryExtractionPrompt renders the prompts asking for the durable facts about the user in
+// the transcript of a conversation turn, skipping the knownFacts.
+func RenderMemoryExtractionPrompt(knownFacts []string, transcript string) (systemPrompt, userPrompt string, err error) {
+	return defaultRegistry.RenderMemoryExtractionPrompt(knownFacts, transcript, Vars{})
+}
 
-	return systemBuf.String(), nil
+// RenderLongTermMemoryPrompt renders the message giving the model the facts remembered about the
+// user that are relevant to the question.
+func RenderLongTermMemoryPrompt(facts []string) (string, error) {
+	return defaultRegistry.RenderLongTermMemoryPrompt(facts, Vars{})
 }
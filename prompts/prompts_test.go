@@ -133,3 +133,56 @@ func TestRenderToolSelectionPromptEmptyTools(t *testing.T) {
 	assert.NotEmpty(t, systemPrompt)
 	assert.Contains(t, systemPrompt, "intelligent tool selection assistant")
 }
+
+func TestRenderStructuredAnswerPrompt(t *testing.T) {
+	prompt, err := RenderStructuredAnswerPrompt(`{"type":"object","properties":{"city":{"type":"string"}}}`)
+
+	assert.NoError(t, err)
+	assert.Contains(t, prompt, "single JSON value")
+	assert.Contains(t, prompt, `{"type":"object","properties":{"city":{"type":"string"}}}`, "Schema should be included verbatim")
+}
+
+func TestRenderAnswerRepairPrompt(t *testing.T) {
+	prompt, err := RenderAnswerRepairPrompt([]string{"city: is required", "population: expected integer, got string"})
+
+	assert.NoError(t, err)
+	assert.Contains(t, prompt, "- city: is required")
+	assert.Contains(t, prompt, "- population: expected integer, got string")
+}
+
+func TestRenderConversationSummaryPrompt(t *testing.T) {
+	systemPrompt, userPrompt, err := RenderConversationSummaryPrompt("", "User: My order 1234 is late")
+
+	assert.NoError(t, err)
+	assert.Contains(t, systemPrompt, "running summary")
+	assert.Contains(t, userPrompt, "User: My order 1234 is late")
+	assert.NotContains(t, userPrompt, "summary so far")
+
+	_, userPrompt, err = RenderConversationSummaryPrompt("The user's order 1234 is late.", "User: It arrived, thanks")
+
+	assert.NoError(t, err)
+	assert.Contains(t, userPrompt, "The user's order 1234 is late.")
+	assert.Contains(t, userPrompt, "User: It arrived, thanks")
+	assert.Contains(t, userPrompt, "merging the conversation that followed")
+}
+
+func TestRenderMemoryExtractionPrompt(t *testing.T) {
+	systemPrompt, userPrompt, err := RenderMemoryExtractionPrompt([]string{"The user lives in Berlin."}, "User: I moved to Munich")
+
+	assert.NoError(t, err)
+	assert.Contains(t, systemPrompt, "NONE")
+	assert.Contains(t, userPrompt, "- The user lives in Berlin.")
+	assert.Contains(t, userPrompt, "User: I moved to Munich")
+
+	_, userPrompt, err = RenderMemoryExtractionPrompt(nil, "User: Hi")
+
+	assert.NoError(t, err)
+	assert.NotContains(t, userPrompt, "already known")
+}
+
+func TestRenderLongTermMemoryPrompt(t *testing.T) {
+	prompt, err := RenderLongTermMemoryPrompt([]string{"The user lives in Berlin.", "The user prefers metric units."})
+
+	assert.NoError(t, err)
+	assert.Contains(t, prompt, "- The user lives in Berlin.\n- The user prefers metric units.\n")
+}
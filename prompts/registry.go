@@ -0,0 +1,321 @@
+package prompts
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+// IrrelevantMarker is the response of the summarization model for tool results irrelevant to the
+// question, which are then dropped. The summarization templates must instruct the model to use it.
+const IrrelevantMarker = "# IRRELEVANT"
+
+// Vars are the variables available to every template in addition to its own: .Tools, .Date,
+// .Metadata and .PreviousToolCalls.
+type Vars struct {
+	// Tools are the tools the agent can use.
+	Tools []ToolInfo
+	// Date is the time of the request; the current time when zero.
+	Date time.Time
+	// Metadata is the metadata of the request, such as the user ID.
+	Metadata map[string]string
+	// PreviousToolCalls are the tool calls already made for the question.
+	PreviousToolCalls []ToolCallInfo
+}
+
+// ToolInfo describes a tool to templates.
+type ToolInfo struct {
+	Name        string
+	Description string
+}
+
+// ToolCallInfo describes a tool call to templates. Arguments are JSON encoded.
+type ToolCallInfo struct {
+	Name      string
+	Arguments string
+}
+
+// commonVars are the names of the Vars fields in template data.
+var commonVars = []string{"Tools", "Date", "Metadata", "PreviousToolCalls"}
+
+// templateSpec lists the variables a template gets and those it must use.
+type templateSpec struct {
+	vars     []string
+	required []string
+}
+
+// templateSpecs are the templates of a Registry, by file name without the .md extension.
+var templateSpecs = map[string]templateSpec{
+	"tool_selection_system":       {vars: []string{"Turn"}},
+	"summarize_context_system":    {vars: []string{"Query", "Content", "ToolInputs"}},
+	"summarize_context_user":      {vars: []string{"Query", "Content", "ToolInputs"}, required: []string{"Query", "Content"}},
+	"structured_answer_system":    {vars: []string{"Schema"}, required: []string{"Schema"}},
+	"structured_answer_repair":    {vars: []string{"Problems"}, required: []string{"Problems"}},
+	"conversation_summary_system": {},
+	"conversation_summary_user":   {vars: []string{"PreviousSummary", "Transcript"}, required: []string{"PreviousSummary", "Transcript"}},
+	"memory_extraction_system":    {},
+	"memory_extraction_user":      {vars: []string{"KnownFacts", "Transcript"}, required: []string{"Transcript"}},
+	"long_term_memory":            {vars: []string{"Facts"}, required: []string{"Facts"}},
+}
+
+// Registry renders the prompt templates of the agent. Templates are read from overrides before
+// the embedded defaults, so prompts can be tuned without forking. A nil *Registry renders the
+// embedded defaults.
+//
+// Templates are parsed once when the registry is created and only executed afterwards, so a
+// Registry is safe for concurrent use, such as summarizing tool results in parallel.
+type Registry struct {
+	templates map[string]*template.Template
+}
+
+// defaultRegistry holds the embedded templates, parsed and validated at package init.
+var defaultRegistry = mustNewRegistry()
+
+// bufferPool reuses the buffers templates are rendered into.
+var bufferPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+func mustNewRegistry() *Registry {
+	r, err := NewRegistry()
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded prompt templates: %v", err))
+	}
+	return r
+}
+
+// NewRegistry creates a registry reading templates from the .md files at the root of overrides,
+// later file systems taking precedence, and from the embedded defaults. Override files are named
+// like the defaults, e.g. tool_selection_system.md. Every template is parsed and checked to use
+// only the variables it gets and all those it requires.
+func NewRegistry(overrides ...fs.FS) (*Registry, error) {
+	embedded, err := fs.Sub(templatesFS, "templates")
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Registry{templates: map[string]*template.Template{}}
+	for i, fsys := range append([]fs.FS{embedded}, overrides...) {
+		files, err := fs.Glob(fsys, "*.md")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			name := strings.TrimSuffix(path.Base(file), ".md")
+			spec, ok := templateSpecs[name]
+			if !ok {
+				if i == 0 {
+					continue
+				}
+				return nil, fmt.Errorf("unknown prompt template %s", file)
+			}
+
+			content, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				return nil, err
+			}
+			tmpl, err := template.New(name).Parse(string(content))
+			if err != nil {
+				return nil, fmt.Errorf("parse prompt template %s: %w", file, err)
+			}
+			if err := spec.validate(tmpl); err != nil {
+				return nil, fmt.Errorf("prompt template %s: %w", file, err)
+			}
+			r.templates[name] = tmpl
+		}
+	}
+
+	for name := range templateSpecs {
+		if r.templates[name] == nil {
+			return nil, fmt.Errorf("missing prompt template %s.md", name)
+		}
+	}
+
+	// The summarizer relies on the marker to drop irrelevant tool results
+	var summarization strings.Builder
+	for _, name := range []string{"summarize_context_system", "summarize_context_user"} {
+		summarization.WriteString(r.templates[name].Root.String())
+	}
+	if !strings.Contains(summarization.String(), IrrelevantMarker) {
+		return nil, fmt.Errorf("summarization prompt templates must instruct the model to respond with %q for irrelevant content", IrrelevantMarker)
+	}
+
+	return r, nil
+}
+
+// NewRegistryFromDir creates a registry reading template overrides from the .md files in dir.
+func NewRegistryFromDir(dir string) (*Registry, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	return NewRegistry(os.DirFS(dir))
+}
+
+// RenderToolSelectionPrompt renders the system prompt of the tool selector for a turn.
+func (r *Registry) RenderToolSelectionPrompt(turn int, vars Vars) (string, error) {
+	return r.render("tool_selection_system", vars, map[string]any{"Turn": turn})
+}
+
+// RenderSummarizationPrompt renders the prompts summarizing a tool result with respect to the
+// user's question and the tool inputs.
+func (r *Registry) RenderSummarizationPrompt(query, content, toolInputs string, vars Vars) (systemPrompt, userPrompt string, err error) {
+	data := map[string]any{"Query": query, "Content": content, "ToolInputs": toolInputs}
+	return r.renderPair("summarize_context_system", "summarize_context_user", vars, data)
+}
+
+// RenderStructuredAnswerPrompt renders the instructions appended to the system prompt when the
+// answer must conform to a JSON schema. schema is the JSON encoded schema.
+func (r *Registry) RenderStructuredAnswerPrompt(schema string, vars Vars) (string, error) {
+	return r.render("structured_answer_system", vars, map[string]any{"Schema": schema})
+}
+
+// RenderAnswerRepairPrompt renders the message asking the model to fix an answer that does not
+// conform to the JSON schema, listing the problems found.
+func (r *Registry) RenderAnswerRepairPrompt(problems []string, vars Vars) (string, error) {
+	return r.render("structured_answer_repair", vars, map[string]any{"Problems": problems})
+}
+
+// RenderConversationSummaryPrompt renders the prompts folding the transcript of older turns of a
+// conversation into its running summary. previousSummary is empty for the first summary.
+func (r *Registry) RenderConversationSummaryPrompt(previousSummary, transcript string, vars Vars) (systemPrompt, userPrompt string, err error) {
+	data := map[string]any{"PreviousSummary": previousSummary, "Transcript": transcript}
+	return r.renderPair("conversation_summary_system", "conversation_summary_user", vars, data)
+}
+
+// RenderMemoryExtractionPrompt renders the prompts asking for the durable facts about the user in
+// the transcript of a conversation turn, skipping the knownFacts.
+func (r *Registry) RenderMemoryExtractionPrompt(knownFacts []string, transcript string, vars Vars) (systemPrompt, userPrompt string, err error) {
+	data := map[string]any{"KnownFacts": knownFacts, "Transcript": transcript}
+	return r.renderPair("memory_extraction_system", "memory_extraction_user", vars, data)
+}
+
+// RenderLongTermMemoryPrompt renders the message giving the model the facts remembered about the
+// user that are relevant to the question.
+func (r *Registry) RenderLongTermMemoryPrompt(facts []string, vars Vars) (string, error) {
+	return r.render("long_term_memory", vars, map[string]any{"Facts": facts})
+}
+
+func (r *Registry) renderPair(systemName, userName string, vars Vars, data map[string]any) (systemPrompt, userPrompt string, err error) {
+	if systemPrompt, err = r.render(systemName, vars, data); err != nil {
+		return "", "", err
+	}
+	if userPrompt, err = r.render(userName, vars, data); err != nil {
+		return "", "", err
+	}
+	return systemPrompt, userPrompt, nil
+}
+
+// render executes the template name with its own data and vars.
+func (r *Registry) render(name string, vars Vars, data map[string]any) (string, error) {
+	if r == nil {
+		r = defaultRegistry
+	}
+
+	date := vars.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	values := map[string]any{
+		"Tools":             vars.Tools,
+		"Date":              date,
+		"Metadata":          vars.Metadata,
+		"PreviousToolCalls": vars.PreviousToolCalls,
+	}
+	for key, value := range data {
+		values[key] = value
+	}
+
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		bufferPool.Put(buf)
+	}()
+	if err := r.templates[name].Execute(buf, values); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// validate checks that tmpl only uses the variables of the spec and all the required ones.
+func (s templateSpec) validate(tmpl *template.Template) error {
+	used := map[string]bool{}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			collectFields(t.Tree.Root, true, used)
+		}
+	}
+
+	for field := range used {
+		if !slices.Contains(s.vars, field) && !slices.Contains(commonVars, field) {
+			return fmt.Errorf("unknown variable .%s, available: %s", field, strings.Join(append(slices.Clone(s.vars), commonVars...), ", "))
+		}
+	}
+	for _, field := range s.required {
+		if !used[field] {
+			return fmt.Errorf("required variable .%s is not used", field)
+		}
+	}
+	return nil
+}
+
+// collectFields adds the top-level data fields used by node to fields. Inside range and with
+// blocks dot is rebound, so only fields accessed through $ are top-level there.
+func collectFields(node parse.Node, topLevel bool, fields map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectFields(child, topLevel, fields)
+		}
+	case *parse.ActionNode:
+		collectFields(n.Pipe, topLevel, fields)
+	case *parse.TemplateNode:
+		collectFields(n.Pipe, topLevel, fields)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			collectFields(cmd, topLevel, fields)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectFields(arg, topLevel, fields)
+		}
+	case *parse.ChainNode:
+		collectFields(n.Node, topLevel, fields)
+	case *parse.FieldNode:
+		if topLevel {
+			fields[n.Ident[0]] = true
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			fields[n.Ident[1]] = true
+		}
+	case *parse.IfNode:
+		collectFields(n.Pipe, topLevel, fields)
+		collectFields(n.List, topLevel, fields)
+		collectFields(n.ElseList, topLevel, fields)
+	case *parse.RangeNode:
+		collectFields(n.Pipe, topLevel, fields)
+		collectFields(n.List, false, fields)
+		collectFields(n.ElseList, topLevel, fields)
+	case *parse.WithNode:
+		collectFields(n.Pipe, topLevel, fields)
+		collectFields(n.List, false, fields)
+		collectFields(n.ElseList, topLevel, fields)
+	}
+}
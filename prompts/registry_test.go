@@ -0,0 +1,209 @@
+package prompts
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"text/template"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRegistryWithoutOverridesMatchesEmbedded(t *testing.T) {
+	r, err := NewRegistry()
+	require.NoError(t, err)
+
+	got, err := r.RenderToolSelectionPrompt(1, Vars{})
+	require.NoError(t, err)
+	want, err := RenderToolSelectionPrompt(1)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestRegistryOverride(t *testing.T) {
+	r, err := NewRegistry(fstest.MapFS{
+		"tool_selection_system.md": {Data: []byte(
+			"Turn {{.Turn}} on {{.Date.Format \"2006-01-02\"}} for {{.Metadata.user_id}}.\n" +
+				"{{range .Tools}}- {{.Name}}: {{.Description}}\n{{end}}" +
+				"{{range .PreviousToolCalls}}Called {{.Name}} with {{.Arguments}}\n{{end}}")},
+	})
+	require.NoError(t, err)
+
+	prompt, err := r.RenderToolSelectionPrompt(2, Vars{
+		Tools:             []ToolInfo{{Name: "search", Description: "Searches the web"}},
+		Date:              time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC),
+		Metadata:          map[string]string{"user_id": "alice"},
+		PreviousToolCalls: []ToolCallInfo{{Name: "search", Arguments: `{"q":"pi"}`}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Turn 2 on 2025-03-14 for alice.\n- search: Searches the web\nCalled search with {\"q\":\"pi\"}\n", prompt)
+
+	// Templates not overridden keep the embedded content
+	structured, err := r.RenderStructuredAnswerPrompt(`{"type":"object"}`, Vars{})
+	require.NoError(t, err)
+	assert.Contains(t, structured, "Answer Format")
+}
+
+func TestRegistryLaterOverridesWin(t *testing.T) {
+	r, err := NewRegistry(
+		fstest.MapFS{"long_term_memory.md": {Data: []byte("First: {{range .Facts}}{{.}}{{end}}")}},
+		fstest.MapFS{"long_term_memory.md": {Data: []byte("Second: {{range .Facts}}{{.}}{{end}}")}},
+	)
+	require.NoError(t, err)
+
+	prompt, err := r.RenderLongTermMemoryPrompt([]string{"likes tea"}, Vars{})
+	require.NoError(t, err)
+	assert.Equal(t, "Second: likes tea", prompt)
+}
+
+func TestRegistryValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		wantErr string
+	}{
+		{
+			name:    "missing required variable",
+			files:   fstest.MapFS{"structured_answer_system.md": {Data: []byte("Respond with JSON.")}},
+			wantErr: "required variable .Schema",
+		},
+		{
+			name:    "required variable only inside range",
+			files:   fstest.MapFS{"long_term_memory.md": {Data: []byte("{{range .Tools}}{{.Facts}}{{end}}")}},
+			wantErr: "required variable .Facts",
+		},
+		{
+			name:    "unknown variable",
+			files:   fstest.MapFS{"tool_selection_system.md": {Data: []byte("Turn {{.Round}}")}},
+			wantErr: "unknown variable .Round",
+		},
+		{
+			name:    "unknown template",
+			files:   fstest.MapFS{"tool_selection.md": {Data: []byte("Select tools")}},
+			wantErr: "unknown prompt template tool_selection.md",
+		},
+		{
+			name:    "syntax error",
+			files:   fstest.MapFS{"tool_selection_system.md": {Data: []byte("Turn {{.Turn")}},
+			wantErr: "parse prompt template tool_selection_system.md",
+		},
+		{
+			name: "summarization without irrelevant marker",
+			files: fstest.MapFS{
+				"summarize_context_system.md": {Data: []byte("Summarize the content.")},
+				"summarize_context_user.md":   {Data: []byte("{{.Query}}\n\n{{.Content}}")},
+			},
+			wantErr: IrrelevantMarker,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRegistry(tt.files)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestRegistryAllowsRootVariablesInsideRange(t *testing.T) {
+	_, err := NewRegistry(fstest.MapFS{
+		"long_term_memory.md": {Data: []byte("{{range .Tools}}{{$.Facts}}{{end}}")},
+	})
+	assert.NoError(t, err)
+}
+
+func TestNewRegistryFromDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "structured_answer_repair.md"),
+		[]byte("Fix: {{range .Problems}}{{.}};{{end}}"), 0o644))
+
+	r, err := NewRegistryFromDir(dir)
+	require.NoError(t, err)
+
+	prompt, err := r.RenderAnswerRepairPrompt([]string{"missing name"}, Vars{})
+	require.NoError(t, err)
+	assert.Equal(t, "Fix: missing name;", prompt)
+
+	_, err = NewRegistryFromDir(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestNilRegistryUsesEmbeddedTemplates(t *testing.T) {
+	var r *Registry
+	prompt, err := r.RenderLongTermMemoryPrompt([]string{"likes tea"}, Vars{})
+	require.NoError(t, err)
+	assert.Contains(t, prompt, "- likes tea")
+}
+
+func TestRegistryConcurrentRender(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			query := fmt.Sprintf("question %d", i)
+			_, userPrompt, err := RenderSummarizationPrompt(query, "content", "")
+			assert.NoError(t, err)
+			assert.Contains(t, userPrompt, query)
+		}()
+	}
+	wg.Wait()
+}
+
+var (
+	benchQuery      = "What is the best time to visit Paris?"
+	benchContent    = strings.Repeat("Paris is pleasant in spring, when parks bloom and crowds are smaller. ", 20)
+	benchToolInputs = "Tool: `search`\n\nParameters:\n- **query**: best time to visit Paris\n"
+)
+
+func BenchmarkRenderSummarizationPrompt(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, _, err := RenderSummarizationPrompt(benchQuery, benchContent, benchToolInputs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRenderSummarizationPromptParallel(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, _, err := RenderSummarizationPrompt(benchQuery, benchContent, benchToolInputs); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+// BenchmarkRenderSummarizationPromptReparsing renders like the registry did before templates were
+// cached, reading and parsing both templates on every call, for comparison.
+func BenchmarkRenderSummarizationPromptReparsing(b *testing.B) {
+	data := map[string]any{"Query": benchQuery, "Content": benchContent, "ToolInputs": benchToolInputs}
+	b.ReportAllocs()
+	for b.Loop() {
+		for _, name := range []string{"summarize_context_system", "summarize_context_user"} {
+			content, err := templatesFS.ReadFile("templates/" + name + ".md")
+			if err != nil {
+				b.Fatal(err)
+			}
+			tmpl, err := template.New(name).Parse(string(content))
+			if err != nil {
+				b.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -623,6 +623,542 @@ func (x *StreamError) GetErrorCode() string {
 	return ""
 }
 
+type ListSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=pageSize,proto3" json:"pageSize,omitempty"`  // Defaults to 20, at most 100.
+	PageToken     string                 `protobuf:"bytes,2,opt,name=pageToken,proto3" json:"pageToken,omitempty"` // nextPageToken of the previous page.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsRequest) Reset() {
+	*x = ListSessionsRequest{}
+	mi := &file_agent_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsRequest) ProtoMessage() {}
+
+func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListSessionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListSessionsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*SessionSummary      `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`           // Most recently updated first.
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"` // Empty on the last page.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsResponse) Reset() {
+	*x = ListSessionsResponse{}
+	mi := &file_agent_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsResponse) ProtoMessage() {}
+
+func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListSessionsResponse) GetSessions() []*SessionSummary {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+func (x *ListSessionsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type SessionSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=sessionId,proto3" json:"sessionId,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"` // First user message.
+	MessageCount  int32                  `protobuf:"varint,3,opt,name=messageCount,proto3" json:"messageCount,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,4,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"` // Unix milliseconds.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionSummary) Reset() {
+	*x = SessionSummary{}
+	mi := &file_agent_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionSummary) ProtoMessage() {}
+
+func (x *SessionSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionSummary.ProtoReflect.Descriptor instead.
+func (*SessionSummary) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SessionSummary) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionSummary) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *SessionSummary) GetMessageCount() int32 {
+	if x != nil {
+		return x.MessageCount
+	}
+	return 0
+}
+
+func (x *SessionSummary) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+type GetSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=sessionId,proto3" json:"sessionId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSessionRequest) Reset() {
+	*x = GetSessionRequest{}
+	mi := &file_agent_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSessionRequest) ProtoMessage() {}
+
+func (x *GetSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSessionRequest.ProtoReflect.Descriptor instead.
+func (*GetSessionRequest) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type DeleteSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=sessionId,proto3" json:"sessionId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSessionRequest) Reset() {
+	*x = DeleteSessionRequest{}
+	mi := &file_agent_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSessionRequest) ProtoMessage() {}
+
+func (x *DeleteSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSessionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSessionRequest) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeleteSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type DeleteSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSessionResponse) Reset() {
+	*x = DeleteSessionResponse{}
+	mi := &file_agent_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSessionResponse) ProtoMessage() {}
+
+func (x *DeleteSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSessionResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSessionResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{12}
+}
+
+type ForkSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=sessionId,proto3" json:"sessionId,omitempty"`
+	MessageCount  int32                  `protobuf:"varint,2,opt,name=messageCount,proto3" json:"messageCount,omitempty"` // Number of leading messages to copy, moved back to a user message boundary; 0 copies all.
+	NewSessionId  string                 `protobuf:"bytes,3,opt,name=newSessionId,proto3" json:"newSessionId,omitempty"`  // Generated when empty.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForkSessionRequest) Reset() {
+	*x = ForkSessionRequest{}
+	mi := &file_agent_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForkSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForkSessionRequest) ProtoMessage() {}
+
+func (x *ForkSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForkSessionRequest.ProtoReflect.Descriptor instead.
+func (*ForkSessionRequest) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ForkSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ForkSessionRequest) GetMessageCount() int32 {
+	if x != nil {
+		return x.MessageCount
+	}
+	return 0
+}
+
+func (x *ForkSessionRequest) GetNewSessionId() string {
+	if x != nil {
+		return x.NewSessionId
+	}
+	return ""
+}
+
+type Session struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=sessionId,proto3" json:"sessionId,omitempty"`
+	Messages      []*SessionMessage      `protobuf:"bytes,2,rep,name=messages,proto3" json:"messages,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,3,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"` // Unix milliseconds.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_agent_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Session) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *Session) GetMessages() []*SessionMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *Session) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+type SessionMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"` // user, assistant or tool
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	ToolCalls     []*SessionToolCall     `protobuf:"bytes,3,rep,name=toolCalls,proto3" json:"toolCalls,omitempty"`
+	ToolName      string                 `protobuf:"bytes,4,opt,name=toolName,proto3" json:"toolName,omitempty"` // Tool whose result a tool message holds.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionMessage) Reset() {
+	*x = SessionMessage{}
+	mi := &file_agent_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionMessage) ProtoMessage() {}
+
+func (x *SessionMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionMessage.ProtoReflect.Descriptor instead.
+func (*SessionMessage) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SessionMessage) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *SessionMessage) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *SessionMessage) GetToolCalls() []*SessionToolCall {
+	if x != nil {
+		return x.ToolCalls
+	}
+	return nil
+}
+
+func (x *SessionMessage) GetToolName() string {
+	if x != nil {
+		return x.ToolName
+	}
+	return ""
+}
+
+type SessionToolCall struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Arguments     string                 `protobuf:"bytes,2,opt,name=arguments,proto3" json:"arguments,omitempty"` // JSON object.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionToolCall) Reset() {
+	*x = SessionToolCall{}
+	mi := &file_agent_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionToolCall) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionToolCall) ProtoMessage() {}
+
+func (x *SessionToolCall) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionToolCall.ProtoReflect.Descriptor instead.
+func (*SessionToolCall) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SessionToolCall) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SessionToolCall) GetArguments() string {
+	if x != nil {
+		return x.Arguments
+	}
+	return ""
+}
+
 var File_agent_proto protoreflect.FileDescriptor
 
 const file_agent_proto_rawDesc = "" +
@@ -675,16 +1211,53 @@ const file_agent_proto_rawDesc = "" +
 	"\vStreamError\x12#\n" +
 	"\rerror_message\x18\x01 \x01(\tR\ferrorMessage\x12\x1d\n" +
 	"\n" +
-	"error_code\x18\x02 \x01(\tR\terrorCode*\xbc\x01\n" +
+	"error_code\x18\x02 \x01(\tR\terrorCode\"O\n" +
+	"\x13ListSessionsRequest\x12\x1a\n" +
+	"\bpageSize\x18\x01 \x01(\x05R\bpageSize\x12\x1c\n" +
+	"\tpageToken\x18\x02 \x01(\tR\tpageToken\"o\n" +
+	"\x14ListSessionsResponse\x121\n" +
+	"\bsessions\x18\x01 \x03(\v2\x15.agent.SessionSummaryR\bsessions\x12$\n" +
+	"\rnextPageToken\x18\x02 \x01(\tR\rnextPageToken\"\x86\x01\n" +
+	"\x0eSessionSummary\x12\x1c\n" +
+	"\tsessionId\x18\x01 \x01(\tR\tsessionId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\"\n" +
+	"\fmessageCount\x18\x03 \x01(\x05R\fmessageCount\x12\x1c\n" +
+	"\tupdatedAt\x18\x04 \x01(\x03R\tupdatedAt\"1\n" +
+	"\x11GetSessionRequest\x12\x1c\n" +
+	"\tsessionId\x18\x01 \x01(\tR\tsessionId\"4\n" +
+	"\x14DeleteSessionRequest\x12\x1c\n" +
+	"\tsessionId\x18\x01 \x01(\tR\tsessionId\"\x17\n" +
+	"\x15DeleteSessionResponse\"z\n" +
+	"\x12ForkSessionRequest\x12\x1c\n" +
+	"\tsessionId\x18\x01 \x01(\tR\tsessionId\x12\"\n" +
+	"\fmessageCount\x18\x02 \x01(\x05R\fmessageCount\x12\"\n" +
+	"\fnewSessionId\x18\x03 \x01(\tR\fnewSessionId\"x\n" +
+	"\aSession\x12\x1c\n" +
+	"\tsessionId\x18\x01 \x01(\tR\tsessionId\x121\n" +
+	"\bmessages\x18\x02 \x03(\v2\x15.agent.SessionMessageR\bmessages\x12\x1c\n" +
+	"\tupdatedAt\x18\x03 \x01(\x03R\tupdatedAt\"\x90\x01\n" +
+	"\x0eSessionMessage\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x124\n" +
+	"\ttoolCalls\x18\x03 \x03(\v2\x16.agent.SessionToolCallR\ttoolCalls\x12\x1a\n" +
+	"\btoolName\x18\x04 \x01(\tR\btoolName\"C\n" +
+	"\x0fSessionToolCall\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\targuments\x18\x02 \x01(\tR\targuments*\xbc\x01\n" +
 	"\x05Stage\x12\x1b\n" +
 	"\x17tool_execution_starting\x10\x00\x12\x19\n" +
 	"\x15tool_execution_failed\x10\x01\x12\x1c\n" +
 	"\x18tool_execution_completed\x10\x02\x12\x1e\n" +
 	"\x1aanswer_generation_starting\x10\x03\x12\x1c\n" +
 	"\x18answer_generation_failed\x10\x04\x12\x1f\n" +
-	"\x1banswer_generation_completed\x10\x052M\n" +
+	"\x1banswer_generation_completed\x10\x052\xdc\x02\n" +
 	"\x05Agent\x12D\n" +
-	"\aExecute\x12\x1c.agent.GenerateAnswerRequest\x1a\x17.agent.AgentStreamChunk\"\x000\x01B+Z)github.com/SaiNageswarS/agent-boot/schemab\x06proto3"
+	"\aExecute\x12\x1c.agent.GenerateAnswerRequest\x1a\x17.agent.AgentStreamChunk\"\x000\x01\x12I\n" +
+	"\fListSessions\x12\x1a.agent.ListSessionsRequest\x1a\x1b.agent.ListSessionsResponse\"\x00\x128\n" +
+	"\n" +
+	"GetSession\x12\x18.agent.GetSessionRequest\x1a\x0e.agent.Session\"\x00\x12L\n" +
+	"\rDeleteSession\x12\x1b.agent.DeleteSessionRequest\x1a\x1c.agent.DeleteSessionResponse\"\x00\x12:\n" +
+	"\vForkSession\x12\x19.agent.ForkSessionRequest\x1a\x0e.agent.Session\"\x00B+Z)github.com/SaiNageswarS/agent-boot/schemab\x06proto3"
 
 var (
 	file_agent_proto_rawDescOnce sync.Once
@@ -699,7 +1272,7 @@ func file_agent_proto_rawDescGZIP() []byte {
 }
 
 var file_agent_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_agent_proto_goTypes = []any{
 	(Stage)(0),                    // 0: agent.Stage
 	(*GenerateAnswerRequest)(nil), // 1: agent.GenerateAnswerRequest
@@ -709,27 +1282,48 @@ var file_agent_proto_goTypes = []any{
 	(*AnswerChunk)(nil),           // 5: agent.AnswerChunk
 	(*StreamComplete)(nil),        // 6: agent.StreamComplete
 	(*StreamError)(nil),           // 7: agent.StreamError
-	nil,                           // 8: agent.GenerateAnswerRequest.MetadataEntry
-	nil,                           // 9: agent.ToolResultChunk.MetadataEntry
-	nil,                           // 10: agent.StreamComplete.MetadataEntry
+	(*ListSessionsRequest)(nil),   // 8: agent.ListSessionsRequest
+	(*ListSessionsResponse)(nil),  // 9: agent.ListSessionsResponse
+	(*SessionSummary)(nil),        // 10: agent.SessionSummary
+	(*GetSessionRequest)(nil),     // 11: agent.GetSessionRequest
+	(*DeleteSessionRequest)(nil),  // 12: agent.DeleteSessionRequest
+	(*DeleteSessionResponse)(nil), // 13: agent.DeleteSessionResponse
+	(*ForkSessionRequest)(nil),    // 14: agent.ForkSessionRequest
+	(*Session)(nil),               // 15: agent.Session
+	(*SessionMessage)(nil),        // 16: agent.SessionMessage
+	(*SessionToolCall)(nil),       // 17: agent.SessionToolCall
+	nil,                           // 18: agent.GenerateAnswerRequest.MetadataEntry
+	nil,                           // 19: agent.ToolResultChunk.MetadataEntry
+	nil,                           // 20: agent.StreamComplete.MetadataEntry
 }
 var file_agent_proto_depIdxs = []int32{
-	8,  // 0: agent.GenerateAnswerRequest.metadata:type_name -> agent.GenerateAnswerRequest.MetadataEntry
+	18, // 0: agent.GenerateAnswerRequest.metadata:type_name -> agent.GenerateAnswerRequest.MetadataEntry
 	3,  // 1: agent.AgentStreamChunk.progressUpdateChunk:type_name -> agent.ProgressUpdateChunk
 	4,  // 2: agent.AgentStreamChunk.toolResultChunk:type_name -> agent.ToolResultChunk
 	5,  // 3: agent.AgentStreamChunk.answer:type_name -> agent.AnswerChunk
 	6,  // 4: agent.AgentStreamChunk.complete:type_name -> agent.StreamComplete
 	7,  // 5: agent.AgentStreamChunk.error:type_name -> agent.StreamError
 	0,  // 6: agent.ProgressUpdateChunk.stage:type_name -> agent.Stage
-	9,  // 7: agent.ToolResultChunk.metadata:type_name -> agent.ToolResultChunk.MetadataEntry
-	10, // 8: agent.StreamComplete.metadata:type_name -> agent.StreamComplete.MetadataEntry
-	1,  // 9: agent.Agent.Execute:input_type -> agent.GenerateAnswerRequest
-	2,  // 10: agent.Agent.Execute:output_type -> agent.AgentStreamChunk
-	10, // [10:11] is the sub-list for method output_type
-	9,  // [9:10] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	19, // 7: agent.ToolResultChunk.metadata:type_name -> agent.ToolResultChunk.MetadataEntry
+	20, // 8: agent.StreamComplete.metadata:type_name -> agent.StreamComplete.MetadataEntry
+	10, // 9: agent.ListSessionsResponse.sessions:type_name -> agent.SessionSummary
+	16, // 10: agent.Session.messages:type_name -> agent.SessionMessage
+	17, // 11: agent.SessionMessage.toolCalls:type_name -> agent.SessionToolCall
+	1,  // 12: agent.Agent.Execute:input_type -> agent.GenerateAnswerRequest
+	8,  // 13: agent.Agent.ListSessions:input_type -> agent.ListSessionsRequest
+	11, // 14: agent.Agent.GetSession:input_type -> agent.GetSessionRequest
+	12, // 15: agent.Agent.DeleteSession:input_type -> agent.DeleteSessionRequest
+	14, // 16: agent.Agent.ForkSession:input_type -> agent.ForkSessionRequest
+	2,  // 17: agent.Agent.Execute:output_type -> agent.AgentStreamChunk
+	9,  // 18: agent.Agent.ListSessions:output_type -> agent.ListSessionsResponse
+	15, // 19: agent.Agent.GetSession:output_type -> agent.Session
+	13, // 20: agent.Agent.DeleteSession:output_type -> agent.DeleteSessionResponse
+	15, // 21: agent.Agent.ForkSession:output_type -> agent.Session
+	17, // [17:22] is the sub-list for method output_type
+	12, // [12:17] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_agent_proto_init() }
@@ -750,7 +1344,7 @@ func file_agent_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_agent_proto_rawDesc), len(file_agent_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   10,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},